@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/MassoudJavadi/filmophilia/api/internal/api"
+	"github.com/MassoudJavadi/filmophilia/api/internal/jobs"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 )
@@ -40,7 +41,14 @@ func main() {
 	}
 	fmt.Println("Connected to PostgreSQL")
 
-	server := api.InitializeServer(dbPool)
+	server, err := api.InitializeServer(dbPool)
+	if err != nil {
+		log.Fatalf("Unable to initialize server: %v", err)
+	}
+
+	// Periodic maintenance; every replica schedules it, one runs each job
+	scheduler := jobs.NewScheduler(dbPool, jobs.Maintenance()...)
+	scheduler.Start()
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -66,7 +74,10 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer shutdownCancel()
 
-	if err := server.Shutdown(shutdownCtx); err != nil {
+	// log.Fatalf skips deferred calls, so the scheduler is stopped first
+	err = server.Shutdown(shutdownCtx)
+	scheduler.Stop()
+	if err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
@@ -2,46 +2,94 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
 	"github.com/MassoudJavadi/filmophilia/api/internal/handler"
 	"github.com/MassoudJavadi/filmophilia/api/internal/middleware"
 	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/token"
+	"github.com/MassoudJavadi/filmophilia/api/internal/service"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 
+// TrustedProxies lists the reverse proxies, as IPs or CIDRs, whose
+// X-Forwarded-For header is believed. With none, the client IP is the address
+// the connection comes from.
+type TrustedProxies []string
+
 type Server struct {
-	httpServer *http.Server
-	router     *gin.Engine
-	db         *pgxpool.Pool
-	authH      *handler.AuthHandler
-	jwt        *token.JWTManager
+	httpServer    *http.Server
+	router        *gin.Engine
+	db            *pgxpool.Pool
+	authH         *handler.AuthHandler
+	movieH        *handler.MovieHandler
+	searchH       *handler.SearchHandler
+	ratingH       *handler.RatingHandler
+	reviewH       *handler.ReviewHandler
+	commentH      *handler.CommentHandler
+	reactionH     *handler.ReactionHandler
+	followH       *handler.FollowHandler
+	userH         *handler.UserHandler
+	watchlistH    *handler.WatchlistHandler
+	feedH         *handler.FeedHandler
+	notificationH *handler.NotificationHandler
+	notifyHub     *service.NotificationHub
+	sessions      *service.SessionValidator
+	adminH        *handler.AdminHandler
+	sessionH      *handler.SessionHandler
+	keysH         *handler.KeysHandler
+	jwt           *token.JWTManager
 }
 
-func NewServer(db *pgxpool.Pool, authH *handler.AuthHandler, jwt *token.JWTManager) *Server {
+func NewServer(db *pgxpool.Pool, authH *handler.AuthHandler, movieH *handler.MovieHandler, searchH *handler.SearchHandler, ratingH *handler.RatingHandler, reviewH *handler.ReviewHandler, commentH *handler.CommentHandler, reactionH *handler.ReactionHandler, followH *handler.FollowHandler, userH *handler.UserHandler, watchlistH *handler.WatchlistHandler, feedH *handler.FeedHandler, notificationH *handler.NotificationHandler, notifyHub *service.NotificationHub, sessions *service.SessionValidator, adminH *handler.AdminHandler, sessionH *handler.SessionHandler, keysH *handler.KeysHandler, jwt *token.JWTManager, proxies TrustedProxies) (*Server, error) {
 	s := &Server{
-		router: gin.Default(),
-		db:     db,
-		authH:  authH,
-		jwt:    jwt,
+		router:        gin.Default(),
+		db:            db,
+		authH:         authH,
+		movieH:        movieH,
+		searchH:       searchH,
+		ratingH:       ratingH,
+		reviewH:       reviewH,
+		commentH:      commentH,
+		reactionH:     reactionH,
+		followH:       followH,
+		userH:         userH,
+		watchlistH:    watchlistH,
+		feedH:         feedH,
+		notificationH: notificationH,
+		notifyHub:     notifyHub,
+		sessions:      sessions,
+		adminH:        adminH,
+		sessionH:      sessionH,
+		keysH:         keysH,
+		jwt:           jwt,
+	}
+
+	// gin trusts every proxy by default, which would let any client choose
+	// the IP recorded for its sessions
+	if err := s.router.SetTrustedProxies(proxies); err != nil {
+		return nil, fmt.Errorf("TRUSTED_PROXIES: %w", err)
 	}
 
 	s.router.Use(cors.New(cors.Config{
         AllowOrigins:     []string{"http://localhost:3000"}, //Client(Next.js) url
         AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-        AllowHeaders:     []string{"Origin", "Authorization", "Content-Type"},
+        AllowHeaders:     []string{"Origin", "Authorization", "Content-Type", middleware.CSRFHeader},
         AllowCredentials: true,
         MaxAge:           12 * time.Hour,
     }))
 	s.setupRoutes()
-	return s
+	return s, nil
 }
 
 func (s *Server) setupRoutes() {
+	s.router.GET("/.well-known/jwks.json", s.keysH.JWKS)
+
 	v1 := s.router.Group("/api/v1")
 
 	// Public routes
@@ -51,16 +99,103 @@ func (s *Server) setupRoutes() {
 		auth.POST("/login", s.authH.Login)
 		auth.POST("/refresh", s.authH.Refresh)
 		auth.POST("/logout", s.authH.Logout)
+		auth.POST("/verify", s.authH.VerifyEmail)
+		auth.POST("/verify/resend", s.authH.ResendVerification)
+		auth.POST("/password/forgot", s.authH.ForgotPassword)
+		auth.POST("/password/reset", s.authH.ResetPassword)
+		auth.POST("/exchange", s.authH.Exchange)
+
+		auth.GET("/:provider", s.authH.ProviderRedirect)
+		auth.GET("/:provider/callback", s.authH.ProviderCallback)
+	}
+
+	// Public content routes; a bearer token is optional and personalises responses
+	public := v1.Group("")
+	public.Use(middleware.OptionalAuthMiddleware(s.jwt, s.sessions))
+	{
+		public.GET("/movies", s.movieH.List)
+		public.GET("/movies/:slug", s.movieH.GetBySlug)
+		public.GET("/movies/:slug/reviews", s.reviewH.ListByMovie)
+		public.GET("/movies/:slug/comments", s.commentH.ListByMovie)
+		public.GET("/genres", s.movieH.ListGenres)
+		public.GET("/search", s.searchH.Search)
 
-		auth.GET("/google", s.authH.GoogleRedirect)
-        auth.GET("/google/callback", s.authH.GoogleCallback)
+		public.GET("/users/:username", s.userH.GetProfile)
+		public.GET("/users/:username/followers", s.followH.Followers)
+		public.GET("/users/:username/following", s.followH.Following)
+		public.GET("/users/:username/mutuals", s.followH.Mutuals)
+		public.GET("/users/:username/ratings", s.ratingH.ListByUser)
+		public.GET("/users/:username/watchlist", s.watchlistH.ListByUser)
+		public.GET("/users/:username/reviews", s.reviewH.ListByUser)
+
+		public.GET("/reviews/:id", s.reviewH.Get)
+		public.GET("/comments/:id/thread", s.commentH.Thread)
 	}
 
 	// Protected routes
 	protected := v1.Group("/")
-	protected.Use(middleware.AuthMiddleware(s.jwt))
+	protected.Use(middleware.AuthMiddleware(s.jwt, s.sessions))
 	{
 		protected.GET("/me", s.authH.GetMe)
+		protected.PATCH("/me", s.userH.UpdateMe)
+		protected.PUT("/me/password", s.authH.ChangePassword)
+		protected.GET("/me/accounts", s.authH.ListAccounts)
+		protected.POST("/me/accounts/:provider/link", s.authH.LinkAccount)
+		protected.DELETE("/me/accounts/:provider", s.authH.UnlinkAccount)
+		protected.GET("/me/sessions", s.sessionH.List)
+		protected.DELETE("/me/sessions", s.sessionH.RevokeOthers)
+		protected.DELETE("/me/sessions/:id", s.sessionH.Revoke)
+
+		protected.PUT("/movies/:slug/rating", s.ratingH.Rate)
+		protected.DELETE("/movies/:slug/rating", s.ratingH.Delete)
+
+		protected.POST("/movies/:slug/reviews", s.reviewH.Create)
+		protected.PATCH("/reviews/:id", s.reviewH.Update)
+		protected.DELETE("/reviews/:id", s.reviewH.Delete)
+
+		protected.POST("/movies/:slug/comments", s.commentH.Create)
+		protected.PATCH("/comments/:id", s.commentH.Update)
+		protected.DELETE("/comments/:id", s.commentH.Delete)
+
+		protected.GET("/feed", s.feedH.Get)
+
+		protected.GET("/notifications/stream", s.notificationH.Stream)
+		protected.GET("/me/notifications", s.notificationH.List)
+		protected.GET("/me/notifications/unread-count", s.notificationH.UnreadCount)
+		protected.POST("/me/notifications/read-all", s.notificationH.MarkAllRead)
+		protected.PUT("/me/notifications/:id/read", s.notificationH.MarkRead)
+		protected.DELETE("/me/notifications/:id", s.notificationH.Delete)
+
+		protected.GET("/me/watchlist", s.watchlistH.List)
+		protected.POST("/movies/:slug/watchlist", s.watchlistH.Add)
+		protected.PATCH("/me/watchlist/:id", s.watchlistH.Update)
+		protected.DELETE("/me/watchlist/:id", s.watchlistH.Remove)
+		protected.PUT("/me/watchlist/:id/position", s.watchlistH.Move)
+		protected.PUT("/me/watchlist/:id/watched", s.watchlistH.MarkWatched)
+		protected.DELETE("/me/watchlist/:id/watched", s.watchlistH.UnmarkWatched)
+
+		protected.POST("/users/:username/follow", s.followH.Follow)
+		protected.DELETE("/users/:username/follow", s.followH.Unfollow)
+
+		protected.PUT("/reviews/:id/reaction", s.reactionH.SetOnReview)
+		protected.DELETE("/reviews/:id/reaction", s.reactionH.ClearOnReview)
+		protected.PUT("/comments/:id/reaction", s.reactionH.SetOnComment)
+		protected.DELETE("/comments/:id/reaction", s.reactionH.ClearOnComment)
+	}
+
+	// Admin routes
+	admin := v1.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(s.jwt, s.sessions), middleware.RequireRole(string(db.RoleADMIN)))
+	{
+		admin.GET("/users/:id", s.adminH.GetUser)
+		admin.GET("/users/:id/status-history", s.adminH.StatusHistory)
+		admin.POST("/users/:id/activate", s.adminH.Activate)
+		admin.POST("/users/:id/suspend", s.adminH.Suspend)
+		admin.POST("/users/:id/ban", s.adminH.Ban)
+		admin.POST("/users/:id/unban", s.adminH.Unban)
+		admin.GET("/users/:id/security-events", s.adminH.SecurityEvents)
+		admin.GET("/users/:id/sessions", s.adminH.ListSessions)
+		admin.DELETE("/users/:id/sessions/:sessionId", s.adminH.RevokeSession)
 	}
 }
 
@@ -69,6 +204,12 @@ func (s *Server) Start(addr string) error {
 		Addr:    addr,
 		Handler: s.router,
 	}
+	// http.Server.Shutdown waits for active handlers, and an SSE handler only
+	// returns once its stream is closed, so the hub is closed first
+	s.httpServer.RegisterOnShutdown(s.notifyHub.Close)
+	s.notifyHub.Start()
+	s.httpServer.RegisterOnShutdown(s.sessions.Close)
+	s.sessions.Start()
 	return s.httpServer.ListenAndServe()
 }
 
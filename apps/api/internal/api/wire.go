@@ -4,10 +4,17 @@
 package api
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/MassoudJavadi/filmophilia/api/internal/db"
 	"github.com/MassoudJavadi/filmophilia/api/internal/handler"
+	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/mailer"
 	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/oauth"
 	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/token"
 	"github.com/MassoudJavadi/filmophilia/api/internal/service"
@@ -15,24 +22,160 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func provideJWTManager() *token.JWTManager {
+const defaultJWTSecret = "dev-secret-change-in-production"
+
+// provideJWTManager signs with JWT_SECRET, or with the RSA or Ed25519 private
+// key in JWT_SIGNING_KEY_FILE when set. JWT_VERIFY_KEY_FILES lists the keys
+// of a rotation that are still accepted. With APP_ENV=production the server
+// will not start on the default secret.
+func provideJWTManager() (*token.JWTManager, error) {
 	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "dev-secret-change-in-production"
+	if secret == "" || secret == defaultJWTSecret {
+		if os.Getenv("APP_ENV") == "production" {
+			return nil, errors.New("JWT_SECRET must be set to a real secret in production")
+		}
+		secret = defaultJWTSecret
+	}
+
+	signingFile := os.Getenv("JWT_SIGNING_KEY_FILE")
+	if signingFile == "" {
+		return token.NewJWTManager(secret), nil
+	}
+	signing, err := token.LoadKey(signingFile)
+	if err != nil {
+		return nil, fmt.Errorf("JWT_SIGNING_KEY_FILE: %w", err)
+	}
+	var previous []token.Key
+	for _, path := range strings.Split(os.Getenv("JWT_VERIFY_KEY_FILES"), ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		k, err := token.LoadKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("JWT_VERIFY_KEY_FILES: %w", err)
+		}
+		previous = append(previous, k)
+	}
+	return token.NewJWTManagerWithKeys(secret, signing, previous...)
+}
+
+func provideAuthConfig() service.AuthConfig {
+	verifyURL := os.Getenv("EMAIL_VERIFY_URL")
+	if verifyURL == "" {
+		verifyURL = "http://localhost:3000/verify-email"
+	}
+	resetURL := os.Getenv("PASSWORD_RESET_URL")
+	if resetURL == "" {
+		resetURL = "http://localhost:3000/reset-password"
+	}
+	allow, _ := strconv.ParseBool(os.Getenv("AUTH_ALLOW_UNVERIFIED_LOGIN"))
+	return service.AuthConfig{
+		AllowUnverifiedLogin: allow,
+		VerifyURL:            verifyURL,
+		ResetURL:             resetURL,
+	}
+}
+
+func provideOAuthConfig() service.OAuthConfig {
+	callbackURL := os.Getenv("OAUTH_FRONTEND_CALLBACK_URL")
+	if callbackURL == "" {
+		callbackURL = "http://localhost:3000/auth/callback"
+	}
+	// The frontend's own origin is always a valid return_to
+	var origins []string
+	if u, err := url.Parse(callbackURL); err == nil && u.Host != "" {
+		origins = append(origins, u.Scheme+"://"+u.Host)
+	}
+	for _, o := range strings.Split(os.Getenv("OAUTH_RETURN_TO_ORIGINS"), ",") {
+		if o = strings.TrimSuffix(strings.TrimSpace(o), "/"); o != "" {
+			origins = append(origins, o)
+		}
+	}
+	return service.OAuthConfig{
+		FrontendCallbackURL: callbackURL,
+		ReturnToOrigins:     origins,
+	}
+}
+
+func provideCookieConfig() handler.CookieConfig {
+	enabled, _ := strconv.ParseBool(os.Getenv("AUTH_COOKIE_MODE"))
+	// Browsers accept Secure cookies on http://localhost, so this only needs
+	// turning off for plain http on other hosts
+	secure := true
+	if v, err := strconv.ParseBool(os.Getenv("AUTH_COOKIE_SECURE")); err == nil {
+		secure = v
+	}
+	sameSite := http.SameSiteLaxMode
+	switch strings.ToLower(os.Getenv("AUTH_COOKIE_SAMESITE")) {
+	case "strict":
+		sameSite = http.SameSiteStrictMode
+	case "none":
+		// Needed when the frontend is on another site; browsers require Secure with it
+		sameSite = http.SameSiteNoneMode
+		secure = true
+	}
+	return handler.CookieConfig{
+		Enabled:  enabled,
+		Domain:   os.Getenv("AUTH_COOKIE_DOMAIN"),
+		Secure:   secure,
+		SameSite: sameSite,
+	}
+}
+
+func provideTrustedProxies() TrustedProxies {
+	var proxies TrustedProxies
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
 	}
-	return token.NewJWTManager(secret)
+	return proxies
 }
 
-func InitializeServer(dbPool *pgxpool.Pool) *Server {
+func InitializeServer(dbPool *pgxpool.Pool) (*Server, error) {
 	wire.Build(
 		wire.Bind(new(db.DBTX), new(*pgxpool.Pool)),
 		db.New,
 		provideJWTManager,
-		oauth.NewGoogleManager,
+		provideAuthConfig,
+		mailer.NewMailer,
+		oauth.NewRegistryFromEnv,
+		provideOAuthConfig,
+		provideCookieConfig,
 		service.NewAuthService,
 		service.NewOAuthService,
+		service.NewMovieService,
+		service.NewSearchService,
+		service.NewNotificationService,
+		service.NewNotificationHub,
+		service.NewSessionValidator,
+		service.NewRatingService,
+		service.NewReviewService,
+		service.NewCommentService,
+		service.NewReactionService,
+		service.NewFollowService,
+		service.NewUserService,
+		service.NewWatchlistService,
+		service.NewFeedService,
+		service.NewAdminService,
+		service.NewSessionService,
 		handler.NewAuthHandler,
+		handler.NewMovieHandler,
+		handler.NewSearchHandler,
+		handler.NewRatingHandler,
+		handler.NewReviewHandler,
+		handler.NewCommentHandler,
+		handler.NewReactionHandler,
+		handler.NewFollowHandler,
+		handler.NewUserHandler,
+		handler.NewWatchlistHandler,
+		handler.NewFeedHandler,
+		handler.NewNotificationHandler,
+		handler.NewAdminHandler,
+		handler.NewSessionHandler,
+		handler.NewKeysHandler,
+		provideTrustedProxies,
 		NewServer,
 	)
-	return &Server{}
+	return &Server{}, nil
 }
@@ -7,34 +7,183 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"github.com/MassoudJavadi/filmophilia/api/internal/db"
 	"github.com/MassoudJavadi/filmophilia/api/internal/handler"
+	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/mailer"
 	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/oauth"
 	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/token"
 	"github.com/MassoudJavadi/filmophilia/api/internal/service"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 )
 
 // Injectors from wire.go:
 
-func InitializeServer(dbPool *pgxpool.Pool) *Server {
+func InitializeServer(dbPool *pgxpool.Pool) (*Server, error) {
 	queries := db.New(dbPool)
-	jwtManager := provideJWTManager()
-	authService := service.NewAuthService(queries, jwtManager)
-	googleManager := oauth.NewGoogleManager()
-	oAuthService := service.NewOAuthService(queries, authService, googleManager)
-	authHandler := handler.NewAuthHandler(authService, oAuthService)
-	server := NewServer(dbPool, authHandler, jwtManager)
-	return server
+	jwtManager, err := provideJWTManager()
+	if err != nil {
+		return nil, err
+	}
+	mailerMailer := mailer.NewMailer()
+	authConfig := provideAuthConfig()
+	authService := service.NewAuthService(dbPool, queries, jwtManager, mailerMailer, authConfig)
+	registry := oauth.NewRegistryFromEnv()
+	oAuthConfig := provideOAuthConfig()
+	oAuthService := service.NewOAuthService(dbPool, queries, authService, jwtManager, registry, oAuthConfig)
+	cookieConfig := provideCookieConfig()
+	authHandler := handler.NewAuthHandler(authService, oAuthService, cookieConfig)
+	movieService := service.NewMovieService(queries)
+	movieHandler := handler.NewMovieHandler(movieService)
+	searchService := service.NewSearchService(dbPool, queries)
+	searchHandler := handler.NewSearchHandler(searchService)
+	notificationService := service.NewNotificationService(queries)
+	ratingService := service.NewRatingService(queries)
+	ratingHandler := handler.NewRatingHandler(ratingService)
+	reviewService := service.NewReviewService(queries, notificationService)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+	commentService := service.NewCommentService(queries, notificationService)
+	commentHandler := handler.NewCommentHandler(commentService)
+	reactionService := service.NewReactionService(queries, notificationService)
+	reactionHandler := handler.NewReactionHandler(reactionService)
+	followService := service.NewFollowService(queries, notificationService)
+	followHandler := handler.NewFollowHandler(followService)
+	userService := service.NewUserService(queries)
+	userHandler := handler.NewUserHandler(userService)
+	watchlistService := service.NewWatchlistService(dbPool, queries)
+	watchlistHandler := handler.NewWatchlistHandler(watchlistService)
+	feedService := service.NewFeedService(queries)
+	feedHandler := handler.NewFeedHandler(feedService)
+	notificationHub := service.NewNotificationHub(dbPool, queries)
+	sessionValidator := service.NewSessionValidator(dbPool, queries)
+	notificationHandler := handler.NewNotificationHandler(notificationService, notificationHub, sessionValidator)
+	adminService := service.NewAdminService(dbPool, queries, notificationService)
+	adminHandler := handler.NewAdminHandler(adminService)
+	sessionService := service.NewSessionService(queries)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+	keysHandler := handler.NewKeysHandler(jwtManager)
+	trustedProxies := provideTrustedProxies()
+	server, err := NewServer(dbPool, authHandler, movieHandler, searchHandler, ratingHandler, reviewHandler, commentHandler, reactionHandler, followHandler, userHandler, watchlistHandler, feedHandler, notificationHandler, notificationHub, sessionValidator, adminHandler, sessionHandler, keysHandler, jwtManager, trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	return server, nil
 }
 
 // wire.go:
 
-func provideJWTManager() *token.JWTManager {
+const defaultJWTSecret = "dev-secret-change-in-production"
+
+// provideJWTManager signs with JWT_SECRET, or with the RSA or Ed25519 private
+// key in JWT_SIGNING_KEY_FILE when set. JWT_VERIFY_KEY_FILES lists the keys
+// of a rotation that are still accepted. With APP_ENV=production the server
+// will not start on the default secret.
+func provideJWTManager() (*token.JWTManager, error) {
 	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "dev-secret-change-in-production"
+	if secret == "" || secret == defaultJWTSecret {
+		if os.Getenv("APP_ENV") == "production" {
+			return nil, errors.New("JWT_SECRET must be set to a real secret in production")
+		}
+		secret = defaultJWTSecret
+	}
+
+	signingFile := os.Getenv("JWT_SIGNING_KEY_FILE")
+	if signingFile == "" {
+		return token.NewJWTManager(secret), nil
+	}
+	signing, err := token.LoadKey(signingFile)
+	if err != nil {
+		return nil, fmt.Errorf("JWT_SIGNING_KEY_FILE: %w", err)
+	}
+	var previous []token.Key
+	for _, path := range strings.Split(os.Getenv("JWT_VERIFY_KEY_FILES"), ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		k, err := token.LoadKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("JWT_VERIFY_KEY_FILES: %w", err)
+		}
+		previous = append(previous, k)
+	}
+	return token.NewJWTManagerWithKeys(secret, signing, previous...)
+}
+
+func provideAuthConfig() service.AuthConfig {
+	verifyURL := os.Getenv("EMAIL_VERIFY_URL")
+	if verifyURL == "" {
+		verifyURL = "http://localhost:3000/verify-email"
+	}
+	resetURL := os.Getenv("PASSWORD_RESET_URL")
+	if resetURL == "" {
+		resetURL = "http://localhost:3000/reset-password"
+	}
+	allow, _ := strconv.ParseBool(os.Getenv("AUTH_ALLOW_UNVERIFIED_LOGIN"))
+	return service.AuthConfig{
+		AllowUnverifiedLogin: allow,
+		VerifyURL:            verifyURL,
+		ResetURL:             resetURL,
+	}
+}
+
+func provideOAuthConfig() service.OAuthConfig {
+	callbackURL := os.Getenv("OAUTH_FRONTEND_CALLBACK_URL")
+	if callbackURL == "" {
+		callbackURL = "http://localhost:3000/auth/callback"
+	}
+	// The frontend's own origin is always a valid return_to
+	var origins []string
+	if u, err := url.Parse(callbackURL); err == nil && u.Host != "" {
+		origins = append(origins, u.Scheme+"://"+u.Host)
+	}
+	for _, o := range strings.Split(os.Getenv("OAUTH_RETURN_TO_ORIGINS"), ",") {
+		if o = strings.TrimSuffix(strings.TrimSpace(o), "/"); o != "" {
+			origins = append(origins, o)
+		}
+	}
+	return service.OAuthConfig{
+		FrontendCallbackURL: callbackURL,
+		ReturnToOrigins:     origins,
+	}
+}
+
+func provideCookieConfig() handler.CookieConfig {
+	enabled, _ := strconv.ParseBool(os.Getenv("AUTH_COOKIE_MODE"))
+	// Browsers accept Secure cookies on http://localhost, so this only needs
+	// turning off for plain http on other hosts
+	secure := true
+	if v, err := strconv.ParseBool(os.Getenv("AUTH_COOKIE_SECURE")); err == nil {
+		secure = v
+	}
+	sameSite := http.SameSiteLaxMode
+	switch strings.ToLower(os.Getenv("AUTH_COOKIE_SAMESITE")) {
+	case "strict":
+		sameSite = http.SameSiteStrictMode
+	case "none":
+		// Needed when the frontend is on another site; browsers require Secure with it
+		sameSite = http.SameSiteNoneMode
+		secure = true
+	}
+	return handler.CookieConfig{
+		Enabled:  enabled,
+		Domain:   os.Getenv("AUTH_COOKIE_DOMAIN"),
+		Secure:   secure,
+		SameSite: sameSite,
+	}
+}
+
+func provideTrustedProxies() TrustedProxies {
+	var proxies TrustedProxies
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
 	}
-	return token.NewJWTManager(secret)
+	return proxies
 }
@@ -339,6 +339,11 @@ type Genre struct {
 	TmdbID pgtype.Int4 `json:"tmdb_id"`
 }
 
+type JobRun struct {
+	Name      string             `json:"name"`
+	LastRunAt pgtype.Timestamptz `json:"last_run_at"`
+}
+
 type Movie struct {
 	ID               int32              `json:"id"`
 	Title            string             `json:"title"`
@@ -380,6 +385,23 @@ type Notification struct {
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
+type OauthExchangeCode struct {
+	CodeHash  string             `json:"code_hash"`
+	UserID    int32              `json:"user_id"`
+	ReturnTo  pgtype.Text        `json:"return_to"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type PasswordResetToken struct {
+	ID        int32              `json:"id"`
+	UserID    int32              `json:"user_id"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
 type Person struct {
 	ID         int32              `json:"id"`
 	Name       string             `json:"name"`
@@ -424,6 +446,23 @@ type Review struct {
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 }
 
+type RotatedRefreshToken struct {
+	TokenHash string             `json:"token_hash"`
+	SessionID string             `json:"session_id"`
+	RotatedAt pgtype.Timestamptz `json:"rotated_at"`
+}
+
+type SecurityEvent struct {
+	ID        int32              `json:"id"`
+	UserID    int32              `json:"user_id"`
+	Type      string             `json:"type"`
+	SessionID pgtype.Text        `json:"session_id"`
+	IpAddress pgtype.Text        `json:"ip_address"`
+	UserAgent pgtype.Text        `json:"user_agent"`
+	Metadata  []byte             `json:"metadata"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
 type Session struct {
 	ID           string             `json:"id"`
 	UserID       int32              `json:"user_id"`
@@ -432,21 +471,25 @@ type Session struct {
 	IpAddress    pgtype.Text        `json:"ip_address"`
 	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
 	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	LastUsedAt   pgtype.Timestamptz `json:"last_used_at"`
 }
 
 type User struct {
-	ID           int32              `json:"id"`
-	Email        string             `json:"email"`
-	Username     string             `json:"username"`
-	PasswordHash string             `json:"password_hash"`
-	DisplayName  pgtype.Text        `json:"display_name"`
-	AvatarUrl    pgtype.Text        `json:"avatar_url"`
-	Bio          pgtype.Text        `json:"bio"`
-	Role         Role               `json:"role"`
-	Status       UserStatus         `json:"status"`
-	IsVerified   bool               `json:"is_verified"`
-	CreatedAt    pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	ID                 int32              `json:"id"`
+	Email              string             `json:"email"`
+	Username           string             `json:"username"`
+	PasswordHash       pgtype.Text        `json:"password_hash"`
+	DisplayName        pgtype.Text        `json:"display_name"`
+	AvatarUrl          pgtype.Text        `json:"avatar_url"`
+	Bio                pgtype.Text        `json:"bio"`
+	Role               Role               `json:"role"`
+	Status             UserStatus         `json:"status"`
+	IsVerified         bool               `json:"is_verified"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	ProfilePrivate     bool               `json:"profile_private"`
+	WatchlistPrivate   bool               `json:"watchlist_private"`
+	VerificationSentAt pgtype.Timestamptz `json:"verification_sent_at"`
 }
 
 type UserStatusLog struct {
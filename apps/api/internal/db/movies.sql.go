@@ -11,6 +11,48 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countMovies = `-- name: CountMovies :one
+
+SELECT COUNT(*) FROM movies m
+WHERE ($1::text IS NULL OR EXISTS (
+        SELECT 1 FROM movie_genres mg
+        JOIN genres g ON g.id = mg.genre_id
+        WHERE mg.movie_id = m.id AND g.slug = $1::text
+    ))
+  AND ($2::int IS NULL OR m.release_date >= make_date($2::int, 1, 1))
+  AND ($3::int IS NULL OR m.release_date < make_date($3::int + 1, 1, 1))
+  AND ($4::int IS NULL OR m.runtime >= $4::int)
+  AND ($5::int IS NULL OR m.runtime <= $5::int)
+  AND ($6::text IS NULL OR lower(m.original_language) = lower($6::text))
+  AND ($7::text IS NULL OR lower(m.country) = lower($7::text))
+`
+
+type CountMoviesParams struct {
+	Genre      pgtype.Text `json:"genre"`
+	YearFrom   pgtype.Int4 `json:"year_from"`
+	YearTo     pgtype.Int4 `json:"year_to"`
+	RuntimeMin pgtype.Int4 `json:"runtime_min"`
+	RuntimeMax pgtype.Int4 `json:"runtime_max"`
+	Language   pgtype.Text `json:"language"`
+	Country    pgtype.Text `json:"country"`
+}
+
+// Total number of movies matching the same filters as ListMovies
+func (q *Queries) CountMovies(ctx context.Context, arg CountMoviesParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countMovies,
+		arg.Genre,
+		arg.YearFrom,
+		arg.YearTo,
+		arg.RuntimeMin,
+		arg.RuntimeMax,
+		arg.Language,
+		arg.Country,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createCredit = `-- name: CreateCredit :exec
 
 INSERT INTO credits (movie_id, person_id, department, role, character)
@@ -86,6 +128,285 @@ func (q *Queries) CreateMovie(ctx context.Context, arg CreateMovieParams) (int32
 	return id, err
 }
 
+const getMovieByID = `-- name: GetMovieByID :one
+
+SELECT id, title, slug, overview, poster_url, backdrop_url, trailer_url, release_date, runtime, content_rating, original_language, country, imdb_id, tmdb_id, user_avg_rating, user_rating_count, created_at, updated_at, imdb_rating, rotten_tomatoes, metacritic_score, letterboxd_rating FROM movies WHERE id = $1
+`
+
+// Fetch a single movie by id, e.g. to read back trigger-maintained stats
+func (q *Queries) GetMovieByID(ctx context.Context, id int32) (Movie, error) {
+	row := q.db.QueryRow(ctx, getMovieByID, id)
+	var i Movie
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.Overview,
+		&i.PosterUrl,
+		&i.BackdropUrl,
+		&i.TrailerUrl,
+		&i.ReleaseDate,
+		&i.Runtime,
+		&i.ContentRating,
+		&i.OriginalLanguage,
+		&i.Country,
+		&i.ImdbID,
+		&i.TmdbID,
+		&i.UserAvgRating,
+		&i.UserRatingCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ImdbRating,
+		&i.RottenTomatoes,
+		&i.MetacriticScore,
+		&i.LetterboxdRating,
+	)
+	return i, err
+}
+
+const getMovieBySlug = `-- name: GetMovieBySlug :one
+
+SELECT id, title, slug, overview, poster_url, backdrop_url, trailer_url, release_date, runtime, content_rating, original_language, country, imdb_id, tmdb_id, user_avg_rating, user_rating_count, created_at, updated_at, imdb_rating, rotten_tomatoes, metacritic_score, letterboxd_rating FROM movies WHERE slug = $1
+`
+
+// Fetch a single movie by its public slug
+func (q *Queries) GetMovieBySlug(ctx context.Context, slug string) (Movie, error) {
+	row := q.db.QueryRow(ctx, getMovieBySlug, slug)
+	var i Movie
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.Overview,
+		&i.PosterUrl,
+		&i.BackdropUrl,
+		&i.TrailerUrl,
+		&i.ReleaseDate,
+		&i.Runtime,
+		&i.ContentRating,
+		&i.OriginalLanguage,
+		&i.Country,
+		&i.ImdbID,
+		&i.TmdbID,
+		&i.UserAvgRating,
+		&i.UserRatingCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ImdbRating,
+		&i.RottenTomatoes,
+		&i.MetacriticScore,
+		&i.LetterboxdRating,
+	)
+	return i, err
+}
+
+const listGenres = `-- name: ListGenres :many
+
+SELECT id, name, slug, tmdb_id FROM genres ORDER BY name
+`
+
+// ============================================================
+// GENRES QUERIES
+// ============================================================
+// All genres, alphabetically, for building catalog filters
+func (q *Queries) ListGenres(ctx context.Context) ([]Genre, error) {
+	rows, err := q.db.Query(ctx, listGenres)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Genre
+	for rows.Next() {
+		var i Genre
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.TmdbID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGenresByMovie = `-- name: ListGenresByMovie :many
+
+SELECT g.id, g.name, g.slug, g.tmdb_id FROM genres g
+JOIN movie_genres mg ON mg.genre_id = g.id
+WHERE mg.movie_id = $1
+ORDER BY g.name
+`
+
+// Genres attached to a single movie
+func (q *Queries) ListGenresByMovie(ctx context.Context, movieID int32) ([]Genre, error) {
+	rows, err := q.db.Query(ctx, listGenresByMovie, movieID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Genre
+	for rows.Next() {
+		var i Genre
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.TmdbID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMovieCredits = `-- name: ListMovieCredits :many
+
+SELECT p.id AS person_id, p.name, p.slug, p.photo_url, c.role, c.character, c."order"
+FROM credits c
+JOIN persons p ON p.id = c.person_id
+WHERE c.movie_id = $1 AND c.department = $2
+ORDER BY c."order" NULLS LAST, c.id
+LIMIT $3
+`
+
+type ListMovieCreditsParams struct {
+	MovieID    int32      `json:"movie_id"`
+	Department Department `json:"department"`
+	Limit      int32      `json:"limit"`
+}
+
+type ListMovieCreditsRow struct {
+	PersonID  int32       `json:"person_id"`
+	Name      string      `json:"name"`
+	Slug      string      `json:"slug"`
+	PhotoUrl  pgtype.Text `json:"photo_url"`
+	Role      string      `json:"role"`
+	Character pgtype.Text `json:"character"`
+	Order     pgtype.Int4 `json:"order"`
+}
+
+// Credits of one department for a movie, in billing order
+func (q *Queries) ListMovieCredits(ctx context.Context, arg ListMovieCreditsParams) ([]ListMovieCreditsRow, error) {
+	rows, err := q.db.Query(ctx, listMovieCredits, arg.MovieID, arg.Department, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMovieCreditsRow
+	for rows.Next() {
+		var i ListMovieCreditsRow
+		if err := rows.Scan(
+			&i.PersonID,
+			&i.Name,
+			&i.Slug,
+			&i.PhotoUrl,
+			&i.Role,
+			&i.Character,
+			&i.Order,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMovies = `-- name: ListMovies :many
+
+SELECT m.id, m.title, m.slug, m.overview, m.poster_url, m.backdrop_url, m.trailer_url, m.release_date, m.runtime, m.content_rating, m.original_language, m.country, m.imdb_id, m.tmdb_id, m.user_avg_rating, m.user_rating_count, m.created_at, m.updated_at, m.imdb_rating, m.rotten_tomatoes, m.metacritic_score, m.letterboxd_rating FROM movies m
+WHERE ($1::text IS NULL OR EXISTS (
+        SELECT 1 FROM movie_genres mg
+        JOIN genres g ON g.id = mg.genre_id
+        WHERE mg.movie_id = m.id AND g.slug = $1::text
+    ))
+  AND ($2::int IS NULL OR m.release_date >= make_date($2::int, 1, 1))
+  AND ($3::int IS NULL OR m.release_date < make_date($3::int + 1, 1, 1))
+  AND ($4::int IS NULL OR m.runtime >= $4::int)
+  AND ($5::int IS NULL OR m.runtime <= $5::int)
+  AND ($6::text IS NULL OR lower(m.original_language) = lower($6::text))
+  AND ($7::text IS NULL OR lower(m.country) = lower($7::text))
+ORDER BY m.release_date DESC NULLS LAST, m.id DESC
+LIMIT $8 OFFSET $9
+`
+
+type ListMoviesParams struct {
+	Genre      pgtype.Text `json:"genre"`
+	YearFrom   pgtype.Int4 `json:"year_from"`
+	YearTo     pgtype.Int4 `json:"year_to"`
+	RuntimeMin pgtype.Int4 `json:"runtime_min"`
+	RuntimeMax pgtype.Int4 `json:"runtime_max"`
+	Language   pgtype.Text `json:"language"`
+	Country    pgtype.Text `json:"country"`
+	Limit      int32       `json:"limit"`
+	Offset     int32       `json:"offset"`
+}
+
+// Paginated catalog listing; a NULL filter is ignored
+func (q *Queries) ListMovies(ctx context.Context, arg ListMoviesParams) ([]Movie, error) {
+	rows, err := q.db.Query(ctx, listMovies,
+		arg.Genre,
+		arg.YearFrom,
+		arg.YearTo,
+		arg.RuntimeMin,
+		arg.RuntimeMax,
+		arg.Language,
+		arg.Country,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Movie
+	for rows.Next() {
+		var i Movie
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.Overview,
+			&i.PosterUrl,
+			&i.BackdropUrl,
+			&i.TrailerUrl,
+			&i.ReleaseDate,
+			&i.Runtime,
+			&i.ContentRating,
+			&i.OriginalLanguage,
+			&i.Country,
+			&i.ImdbID,
+			&i.TmdbID,
+			&i.UserAvgRating,
+			&i.UserRatingCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ImdbRating,
+			&i.RottenTomatoes,
+			&i.MetacriticScore,
+			&i.LetterboxdRating,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const upsertPerson = `-- name: UpsertPerson :one
 
 INSERT INTO persons (name, slug)
@@ -11,10 +11,25 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const createRotatedRefreshToken = `-- name: CreateRotatedRefreshToken :exec
+INSERT INTO rotated_refresh_tokens (token_hash, session_id)
+VALUES ($1, $2)
+`
+
+type CreateRotatedRefreshTokenParams struct {
+	TokenHash string `json:"token_hash"`
+	SessionID string `json:"session_id"`
+}
+
+func (q *Queries) CreateRotatedRefreshToken(ctx context.Context, arg CreateRotatedRefreshTokenParams) error {
+	_, err := q.db.Exec(ctx, createRotatedRefreshToken, arg.TokenHash, arg.SessionID)
+	return err
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO sessions (id, user_id, refresh_token, user_agent, ip_address, expires_at)
 VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, user_id, refresh_token, user_agent, ip_address, expires_at, created_at
+RETURNING id, user_id, refresh_token, user_agent, ip_address, expires_at, created_at, last_used_at
 `
 
 type CreateSessionParams struct {
@@ -44,17 +59,52 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.IpAddress,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.LastUsedAt,
 	)
 	return i, err
 }
 
-const deleteExpiredSessions = `-- name: DeleteExpiredSessions :exec
+const deleteExpiredSessions = `-- name: DeleteExpiredSessions :execrows
 DELETE FROM sessions WHERE expires_at < NOW()
 `
 
-func (q *Queries) DeleteExpiredSessions(ctx context.Context) error {
-	_, err := q.db.Exec(ctx, deleteExpiredSessions)
-	return err
+func (q *Queries) DeleteExpiredSessions(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredSessions)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteOldRotatedRefreshTokens = `-- name: DeleteOldRotatedRefreshTokens :execrows
+
+DELETE FROM rotated_refresh_tokens WHERE rotated_at < $1
+`
+
+// Rotated tokens this old had expired anyway, so their reuse proves nothing
+func (q *Queries) DeleteOldRotatedRefreshTokens(ctx context.Context, before pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOldRotatedRefreshTokens, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteOtherUserSessions = `-- name: DeleteOtherUserSessions :execrows
+DELETE FROM sessions WHERE user_id = $1 AND id <> $2
+`
+
+type DeleteOtherUserSessionsParams struct {
+	UserID int32  `json:"user_id"`
+	KeepID string `json:"keep_id"`
+}
+
+func (q *Queries) DeleteOtherUserSessions(ctx context.Context, arg DeleteOtherUserSessionsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOtherUserSessions, arg.UserID, arg.KeepID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const deleteSession = `-- name: DeleteSession :exec
@@ -76,6 +126,23 @@ func (q *Queries) DeleteSessionByRefreshToken(ctx context.Context, refreshToken
 	return err
 }
 
+const deleteUserSession = `-- name: DeleteUserSession :execrows
+DELETE FROM sessions WHERE id = $1 AND user_id = $2
+`
+
+type DeleteUserSessionParams struct {
+	ID     string `json:"id"`
+	UserID int32  `json:"user_id"`
+}
+
+func (q *Queries) DeleteUserSession(ctx context.Context, arg DeleteUserSessionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUserSession, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deleteUserSessions = `-- name: DeleteUserSessions :exec
 DELETE FROM sessions WHERE user_id = $1
 `
@@ -85,8 +152,59 @@ func (q *Queries) DeleteUserSessions(ctx context.Context, userID int32) error {
 	return err
 }
 
+const getActiveSession = `-- name: GetActiveSession :one
+
+SELECT s.user_id, u.status
+FROM sessions s
+JOIN users u ON u.id = s.user_id
+WHERE s.id = $1 AND s.expires_at > NOW()
+`
+
+type GetActiveSessionRow struct {
+	UserID int32      `json:"user_id"`
+	Status UserStatus `json:"status"`
+}
+
+// The owner of a session that still grants access, checked on every
+// authenticated request (through a cache)
+func (q *Queries) GetActiveSession(ctx context.Context, id string) (GetActiveSessionRow, error) {
+	row := q.db.QueryRow(ctx, getActiveSession, id)
+	var i GetActiveSessionRow
+	err := row.Scan(
+		&i.UserID,
+		&i.Status,
+	)
+	return i, err
+}
+
+const getRotatedRefreshToken = `-- name: GetRotatedRefreshToken :one
+
+SELECT r.session_id, r.rotated_at, s.user_id
+FROM rotated_refresh_tokens r
+JOIN sessions s ON s.id = r.session_id
+WHERE r.token_hash = $1
+`
+
+type GetRotatedRefreshTokenRow struct {
+	SessionID string             `json:"session_id"`
+	RotatedAt pgtype.Timestamptz `json:"rotated_at"`
+	UserID    int32              `json:"user_id"`
+}
+
+// Finds the family a rotated-out token belonged to
+func (q *Queries) GetRotatedRefreshToken(ctx context.Context, tokenHash string) (GetRotatedRefreshTokenRow, error) {
+	row := q.db.QueryRow(ctx, getRotatedRefreshToken, tokenHash)
+	var i GetRotatedRefreshTokenRow
+	err := row.Scan(
+		&i.SessionID,
+		&i.RotatedAt,
+		&i.UserID,
+	)
+	return i, err
+}
+
 const getSessionByID = `-- name: GetSessionByID :one
-SELECT id, user_id, refresh_token, user_agent, ip_address, expires_at, created_at FROM sessions WHERE id = $1
+SELECT id, user_id, refresh_token, user_agent, ip_address, expires_at, created_at, last_used_at FROM sessions WHERE id = $1
 `
 
 func (q *Queries) GetSessionByID(ctx context.Context, id string) (Session, error) {
@@ -100,14 +218,17 @@ func (q *Queries) GetSessionByID(ctx context.Context, id string) (Session, error
 		&i.IpAddress,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.LastUsedAt,
 	)
 	return i, err
 }
 
 const getSessionByRefreshToken = `-- name: GetSessionByRefreshToken :one
-SELECT id, user_id, refresh_token, user_agent, ip_address, expires_at, created_at FROM sessions WHERE refresh_token = $1 LIMIT 1
+
+SELECT id, user_id, refresh_token, user_agent, ip_address, expires_at, created_at, last_used_at FROM sessions WHERE refresh_token = $1 LIMIT 1
 `
 
+// refresh_token holds the SHA-256 hex of the token, never the token itself
 func (q *Queries) GetSessionByRefreshToken(ctx context.Context, refreshToken pgtype.Text) (Session, error) {
 	row := q.db.QueryRow(ctx, getSessionByRefreshToken, refreshToken)
 	var i Session
@@ -119,10 +240,83 @@ func (q *Queries) GetSessionByRefreshToken(ctx context.Context, refreshToken pgt
 		&i.IpAddress,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.LastUsedAt,
 	)
 	return i, err
 }
 
+const listUserSessions = `-- name: ListUserSessions :many
+SELECT id, user_id, refresh_token, user_agent, ip_address, expires_at, created_at, last_used_at FROM sessions
+WHERE user_id = $1 AND expires_at > NOW()
+ORDER BY last_used_at DESC, created_at DESC
+`
+
+func (q *Queries) ListUserSessions(ctx context.Context, userID int32) ([]Session, error) {
+	rows, err := q.db.Query(ctx, listUserSessions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Session
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.RefreshToken,
+			&i.UserAgent,
+			&i.IpAddress,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rotateSession = `-- name: RotateSession :execrows
+
+UPDATE sessions
+SET refresh_token = $1,
+    expires_at = $2,
+    user_agent = $3,
+    ip_address = $4,
+    last_used_at = NOW()
+WHERE id = $5 AND refresh_token = $6
+`
+
+type RotateSessionParams struct {
+	NewRefreshToken pgtype.Text        `json:"new_refresh_token"`
+	ExpiresAt       pgtype.Timestamptz `json:"expires_at"`
+	UserAgent       pgtype.Text        `json:"user_agent"`
+	IpAddress       pgtype.Text        `json:"ip_address"`
+	ID              string             `json:"id"`
+	OldRefreshToken pgtype.Text        `json:"old_refresh_token"`
+}
+
+// Swaps the refresh token of a session in place; zero rows means the old
+// token was already rotated away by a concurrent refresh
+func (q *Queries) RotateSession(ctx context.Context, arg RotateSessionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rotateSession,
+		arg.NewRefreshToken,
+		arg.ExpiresAt,
+		arg.UserAgent,
+		arg.IpAddress,
+		arg.ID,
+		arg.OldRefreshToken,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateSession = `-- name: UpdateSession :exec
 UPDATE sessions 
 SET refresh_token = $2, expires_at = $3 
@@ -11,16 +11,94 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const claimVerificationSend = `-- name: ClaimVerificationSend :execrows
+
+UPDATE users SET verification_sent_at = NOW()
+WHERE id = $1
+  AND is_verified = FALSE
+  AND (verification_sent_at IS NULL
+       OR verification_sent_at <= NOW() - make_interval(secs => $2::int))
+`
+
+type ClaimVerificationSendParams struct {
+	ID              int32 `json:"id"`
+	CooldownSeconds int32 `json:"cooldown_seconds"`
+}
+
+// Stamps a verification send unless the user is verified or one went out
+// within the cooldown; zero rows means the send must be skipped
+func (q *Queries) ClaimVerificationSend(ctx context.Context, arg ClaimVerificationSendParams) (int64, error) {
+	result, err := q.db.Exec(ctx, claimVerificationSend, arg.ID, arg.CooldownSeconds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const countUserStatusLogs = `-- name: CountUserStatusLogs :one
+SELECT COUNT(*) FROM user_status_logs WHERE user_id = $1
+`
+
+func (q *Queries) CountUserStatusLogs(ctx context.Context, userID int32) (int64, error) {
+	row := q.db.QueryRow(ctx, countUserStatusLogs, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createOAuthUser = `-- name: CreateOAuthUser :one
+
+INSERT INTO users (email, username, display_name, avatar_url, status, is_verified)
+VALUES ($1, $2, $3, $4, 'ACTIVE', TRUE)
+RETURNING id, email, username, password_hash, display_name, avatar_url, bio, role, status, is_verified, created_at, updated_at, profile_private, watchlist_private, verification_sent_at
+`
+
+type CreateOAuthUserParams struct {
+	Email       string      `json:"email"`
+	Username    string      `json:"username"`
+	DisplayName pgtype.Text `json:"display_name"`
+	AvatarUrl   pgtype.Text `json:"avatar_url"`
+}
+
+// The provider has verified the email, so the account starts out active
+func (q *Queries) CreateOAuthUser(ctx context.Context, arg CreateOAuthUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, createOAuthUser,
+		arg.Email,
+		arg.Username,
+		arg.DisplayName,
+		arg.AvatarUrl,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.PasswordHash,
+		&i.DisplayName,
+		&i.AvatarUrl,
+		&i.Bio,
+		&i.Role,
+		&i.Status,
+		&i.IsVerified,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProfilePrivate,
+		&i.WatchlistPrivate,
+		&i.VerificationSentAt,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (email, username, password_hash, display_name)
 VALUES ($1, $2, $3, $4)
-RETURNING id, email, username, password_hash, display_name, avatar_url, bio, role, status, is_verified, created_at, updated_at
+RETURNING id, email, username, password_hash, display_name, avatar_url, bio, role, status, is_verified, created_at, updated_at, profile_private, watchlist_private, verification_sent_at
 `
 
 type CreateUserParams struct {
 	Email        string      `json:"email"`
 	Username     string      `json:"username"`
-	PasswordHash string      `json:"password_hash"`
+	PasswordHash pgtype.Text `json:"password_hash"`
 	DisplayName  pgtype.Text `json:"display_name"`
 }
 
@@ -45,12 +123,15 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.IsVerified,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProfilePrivate,
+		&i.WatchlistPrivate,
+		&i.VerificationSentAt,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, username, password_hash, display_name, avatar_url, bio, role, status, is_verified, created_at, updated_at FROM users WHERE email = $1
+SELECT id, email, username, password_hash, display_name, avatar_url, bio, role, status, is_verified, created_at, updated_at, profile_private, watchlist_private, verification_sent_at FROM users WHERE email = $1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -69,12 +150,15 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.IsVerified,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProfilePrivate,
+		&i.WatchlistPrivate,
+		&i.VerificationSentAt,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, email, username, password_hash, display_name, avatar_url, bio, role, status, is_verified, created_at, updated_at FROM users WHERE id = $1
+SELECT id, email, username, password_hash, display_name, avatar_url, bio, role, status, is_verified, created_at, updated_at, profile_private, watchlist_private, verification_sent_at FROM users WHERE id = $1
 `
 
 func (q *Queries) GetUserByID(ctx context.Context, id int32) (User, error) {
@@ -93,12 +177,15 @@ func (q *Queries) GetUserByID(ctx context.Context, id int32) (User, error) {
 		&i.IsVerified,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProfilePrivate,
+		&i.WatchlistPrivate,
+		&i.VerificationSentAt,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, email, username, password_hash, display_name, avatar_url, bio, role, status, is_verified, created_at, updated_at FROM users WHERE username = $1
+SELECT id, email, username, password_hash, display_name, avatar_url, bio, role, status, is_verified, created_at, updated_at, profile_private, watchlist_private, verification_sent_at FROM users WHERE username = $1
 `
 
 func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
@@ -117,6 +204,242 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.IsVerified,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProfilePrivate,
+		&i.WatchlistPrivate,
+		&i.VerificationSentAt,
+	)
+	return i, err
+}
+
+const getUserRatingHistogram = `-- name: GetUserRatingHistogram :many
+SELECT score, COUNT(*) AS count
+FROM ratings
+WHERE user_id = $1
+GROUP BY score
+ORDER BY score
+`
+
+type GetUserRatingHistogramRow struct {
+	Score int32 `json:"score"`
+	Count int64 `json:"count"`
+}
+
+func (q *Queries) GetUserRatingHistogram(ctx context.Context, userID int32) ([]GetUserRatingHistogramRow, error) {
+	rows, err := q.db.Query(ctx, getUserRatingHistogram, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserRatingHistogramRow
+	for rows.Next() {
+		var i GetUserRatingHistogramRow
+		if err := rows.Scan(
+			&i.Score,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserStats = `-- name: GetUserStats :one
+SELECT
+    (SELECT COUNT(*) FROM ratings r WHERE r.user_id = $1) AS rating_count,
+    (SELECT COUNT(*) FROM reviews rv WHERE rv.user_id = $1) AS review_count,
+    (SELECT COUNT(*) FROM follows f WHERE f.following_id = $1) AS follower_count,
+    (SELECT COUNT(*) FROM follows f WHERE f.follower_id = $1) AS following_count,
+    (SELECT COUNT(*) FROM watchlists w WHERE w.user_id = $1) AS watchlist_count
+`
+
+type GetUserStatsRow struct {
+	RatingCount    int64 `json:"rating_count"`
+	ReviewCount    int64 `json:"review_count"`
+	FollowerCount  int64 `json:"follower_count"`
+	FollowingCount int64 `json:"following_count"`
+	WatchlistCount int64 `json:"watchlist_count"`
+}
+
+func (q *Queries) GetUserStats(ctx context.Context, userID int32) (GetUserStatsRow, error) {
+	row := q.db.QueryRow(ctx, getUserStats, userID)
+	var i GetUserStatsRow
+	err := row.Scan(
+		&i.RatingCount,
+		&i.ReviewCount,
+		&i.FollowerCount,
+		&i.FollowingCount,
+		&i.WatchlistCount,
+	)
+	return i, err
+}
+
+const listUserStatusLogs = `-- name: ListUserStatusLogs :many
+
+SELECT l.id, l.old_status, l.new_status, l.reason, l.changed_by, l.created_at,
+       a.username AS changed_by_username
+FROM user_status_logs l
+LEFT JOIN users a ON a.id = l.changed_by
+WHERE l.user_id = $1
+ORDER BY l.created_at DESC, l.id DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListUserStatusLogsParams struct {
+	UserID int32 `json:"user_id"`
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+type ListUserStatusLogsRow struct {
+	ID                int32              `json:"id"`
+	OldStatus         NullUserStatus     `json:"old_status"`
+	NewStatus         UserStatus         `json:"new_status"`
+	Reason            pgtype.Text        `json:"reason"`
+	ChangedBy         pgtype.Int4        `json:"changed_by"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	ChangedByUsername pgtype.Text        `json:"changed_by_username"`
+}
+
+// Status history of a user, newest first, with the acting admin's username
+func (q *Queries) ListUserStatusLogs(ctx context.Context, arg ListUserStatusLogsParams) ([]ListUserStatusLogsRow, error) {
+	rows, err := q.db.Query(ctx, listUserStatusLogs, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUserStatusLogsRow
+	for rows.Next() {
+		var i ListUserStatusLogsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OldStatus,
+			&i.NewStatus,
+			&i.Reason,
+			&i.ChangedBy,
+			&i.CreatedAt,
+			&i.ChangedByUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setStatusChangeContext = `-- name: SetStatusChangeContext :exec
+
+SELECT set_config('filmophilia.status_reason', $1::text, true),
+       set_config('filmophilia.status_changed_by', $2::int::text, true)
+`
+
+type SetStatusChangeContextParams struct {
+	Reason    string `json:"reason"`
+	ChangedBy int32  `json:"changed_by"`
+}
+
+// Hands reason and acting admin to log_user_status_change() for the current transaction
+func (q *Queries) SetStatusChangeContext(ctx context.Context, arg SetStatusChangeContextParams) error {
+	_, err := q.db.Exec(ctx, setStatusChangeContext, arg.Reason, arg.ChangedBy)
+	return err
+}
+
+const setStatusChangeReason = `-- name: SetStatusChangeReason :exec
+
+SELECT set_config('filmophilia.status_reason', $1::text, true)
+`
+
+// Like SetStatusChangeContext for changes the user made themselves
+func (q *Queries) SetStatusChangeReason(ctx context.Context, reason string) error {
+	_, err := q.db.Exec(ctx, setStatusChangeReason, reason)
+	return err
+}
+
+const setUserAvatarIfEmpty = `-- name: SetUserAvatarIfEmpty :exec
+UPDATE users SET avatar_url = $2 WHERE id = $1 AND avatar_url IS NULL
+`
+
+type SetUserAvatarIfEmptyParams struct {
+	ID        int32       `json:"id"`
+	AvatarUrl pgtype.Text `json:"avatar_url"`
+}
+
+func (q *Queries) SetUserAvatarIfEmpty(ctx context.Context, arg SetUserAvatarIfEmptyParams) error {
+	_, err := q.db.Exec(ctx, setUserAvatarIfEmpty, arg.ID, arg.AvatarUrl)
+	return err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = $2 WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
+	ID           int32       `json:"id"`
+	PasswordHash pgtype.Text `json:"password_hash"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, updateUserPassword, arg.ID, arg.PasswordHash)
+	return err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+
+UPDATE users SET
+    display_name = CASE WHEN $1::text IS NULL THEN display_name
+                        ELSE NULLIF($1::text, '') END,
+    bio = CASE WHEN $2::text IS NULL THEN bio
+               ELSE NULLIF($2::text, '') END,
+    avatar_url = CASE WHEN $3::text IS NULL THEN avatar_url
+                      ELSE NULLIF($3::text, '') END,
+    profile_private = COALESCE($4::boolean, profile_private),
+    watchlist_private = COALESCE($5::boolean, watchlist_private)
+WHERE id = $6
+RETURNING id, email, username, password_hash, display_name, avatar_url, bio, role, status, is_verified, created_at, updated_at, profile_private, watchlist_private, verification_sent_at
+`
+
+type UpdateUserProfileParams struct {
+	DisplayName      pgtype.Text `json:"display_name"`
+	Bio              pgtype.Text `json:"bio"`
+	AvatarUrl        pgtype.Text `json:"avatar_url"`
+	ProfilePrivate   pgtype.Bool `json:"profile_private"`
+	WatchlistPrivate pgtype.Bool `json:"watchlist_private"`
+	ID               int32       `json:"id"`
+}
+
+// Omitted (NULL) fields are kept; an empty string clears a text field
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserProfile,
+		arg.DisplayName,
+		arg.Bio,
+		arg.AvatarUrl,
+		arg.ProfilePrivate,
+		arg.WatchlistPrivate,
+		arg.ID,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.PasswordHash,
+		&i.DisplayName,
+		&i.AvatarUrl,
+		&i.Bio,
+		&i.Role,
+		&i.Status,
+		&i.IsVerified,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProfilePrivate,
+		&i.WatchlistPrivate,
+		&i.VerificationSentAt,
 	)
 	return i, err
 }
@@ -134,3 +457,52 @@ func (q *Queries) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusPara
 	_, err := q.db.Exec(ctx, updateUserStatus, arg.ID, arg.Status)
 	return err
 }
+
+const usernameExists = `-- name: UsernameExists :one
+SELECT EXISTS (SELECT 1 FROM users WHERE username = $1)
+`
+
+func (q *Queries) UsernameExists(ctx context.Context, username string) (bool, error) {
+	row := q.db.QueryRow(ctx, usernameExists, username)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+
+UPDATE users SET
+    is_verified = TRUE,
+    status = CASE WHEN status = 'PENDING' THEN 'ACTIVE'::user_status ELSE status END
+WHERE id = $1 AND email = $2
+RETURNING id, email, username, password_hash, display_name, avatar_url, bio, role, status, is_verified, created_at, updated_at, profile_private, watchlist_private, verification_sent_at
+`
+
+type VerifyUserEmailParams struct {
+	ID    int32  `json:"id"`
+	Email string `json:"email"`
+}
+
+// Only succeeds while the email is still the one the token was issued for
+func (q *Queries) VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, verifyUserEmail, arg.ID, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.PasswordHash,
+		&i.DisplayName,
+		&i.AvatarUrl,
+		&i.Bio,
+		&i.Role,
+		&i.Status,
+		&i.IsVerified,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProfilePrivate,
+		&i.WatchlistPrivate,
+		&i.VerificationSentAt,
+	)
+	return i, err
+}
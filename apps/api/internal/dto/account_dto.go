@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+// AccountResponse is a sign-in provider linked to the user
+type AccountResponse struct {
+	Provider string    `json:"provider"`
+	LinkedAt time.Time `json:"linked_at"`
+}
+
+// LinkURLResponse is the provider page the browser goes to for linking
+type LinkURLResponse struct {
+	URL string `json:"url"`
+}
+
+type ExchangeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// ExchangeResponse finishes a provider sign-in; ReturnTo is where the user
+// asked to land, already checked against the whitelist
+type ExchangeResponse struct {
+	AuthResponse
+	ReturnTo string `json:"return_to,omitempty"`
+}
@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+// Moderation actions exposed under /admin/users/:id
+const (
+	ModerationActivate = "activate"
+	ModerationSuspend  = "suspend"
+	ModerationBan      = "ban"
+	ModerationUnban    = "unban"
+)
+
+// ModerationRequest is the body of every moderation action; the reason is
+// stored in the status history and shown to the user
+type ModerationRequest struct {
+	Reason string `json:"reason" binding:"required,min=3,max=1000"`
+}
+
+// AdminUserResponse is the moderator's view of an account
+type AdminUserResponse struct {
+	ID          int32     `json:"id"`
+	Email       string    `json:"email"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	Role        string    `json:"role"`
+	Status      string    `json:"status"`
+	IsVerified  bool      `json:"is_verified"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// StatusLogResponse is one entry of a user's status history. ChangedBy is
+// null for changes made outside the admin API.
+type StatusLogResponse struct {
+	ID        int32           `json:"id"`
+	OldStatus *string         `json:"old_status"`
+	NewStatus string          `json:"new_status"`
+	Reason    *string         `json:"reason"`
+	ChangedBy *AuthorResponse `json:"changed_by"`
+	CreatedAt time.Time       `json:"created_at"`
+}
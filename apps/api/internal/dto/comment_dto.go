@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+// DeletedCommentContent replaces the body of soft-deleted comments
+const DeletedCommentContent = "[deleted]"
+
+// CreateCommentRequest is the body of POST /movies/:slug/comments
+type CreateCommentRequest struct {
+	Content  string `json:"content" binding:"required,max=5000"`
+	ParentID *int32 `json:"parent_id" binding:"omitempty,min=1"`
+}
+
+// UpdateCommentRequest is the body of PATCH /comments/:id
+type UpdateCommentRequest struct {
+	Content string `json:"content" binding:"required,max=5000"`
+}
+
+// CommentTreeQuery is the query string for reading comment threads
+type CommentTreeQuery struct {
+	CursorQuery
+	Depth int32 `form:"depth,default=3" binding:"min=0,max=10"`
+}
+
+// CommentResponse is a node of a comment thread
+type CommentResponse struct {
+	ID             int32             `json:"id"`
+	ParentID       *int32            `json:"parent_id"`
+	Content        string            `json:"content"`
+	IsDeleted      bool              `json:"is_deleted"`
+	Author         *AuthorResponse   `json:"author"`
+	LikeCount      int32             `json:"like_count"`
+	ReplyCount     int64             `json:"reply_count"`
+	HasMoreReplies bool              `json:"has_more_replies"`
+	Reactions      ReactionSummary   `json:"reactions"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	Replies        []CommentResponse `json:"replies"`
+}
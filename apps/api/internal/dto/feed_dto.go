@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Activity actions recorded for the feed
+const (
+	ActivityRated       = "rated"
+	ActivityReviewed    = "reviewed"
+	ActivityFollowed    = "followed"
+	ActivityWatchlisted = "watchlisted"
+)
+
+// FeedReviewRef identifies the review a "reviewed" activity points at
+type FeedReviewRef struct {
+	ID    int32   `json:"id"`
+	Title *string `json:"title"`
+}
+
+// FeedItemResponse is a single activity; exactly one of Movie or User is set,
+// and Review is set alongside Movie for reviews
+type FeedItemResponse struct {
+	ActivityID int32             `json:"activity_id"`
+	Movie      *MovieRefResponse `json:"movie,omitempty"`
+	Review     *FeedReviewRef    `json:"review,omitempty"`
+	User       *AuthorResponse   `json:"user,omitempty"`
+	Metadata   json.RawMessage   `json:"metadata,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// FeedEntryResponse is one line of the timeline. A burst of the same action by
+// the same user ("rated 12 films") is collapsed into one entry whose Count is
+// the size of the burst and whose Items hold the most recent few of them.
+type FeedEntryResponse struct {
+	Action    string             `json:"action"`
+	Actor     AuthorResponse     `json:"actor"`
+	Count     int                `json:"count"`
+	Items     []FeedItemResponse `json:"items"`
+	CreatedAt time.Time          `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// FollowUserResponse is an entry of a followers/following/mutuals list
+type FollowUserResponse struct {
+	ID          int32     `json:"id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	AvatarURL   *string   `json:"avatar_url"`
+	FollowedAt  time.Time `json:"followed_at"`
+}
+
+// FollowStatusResponse is returned after following or unfollowing a user
+type FollowStatusResponse struct {
+	Following     bool  `json:"following"`
+	FollowerCount int64 `json:"follower_count"`
+}
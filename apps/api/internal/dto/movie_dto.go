@@ -0,0 +1,77 @@
+package dto
+
+// MovieFilterQuery holds the optional catalog filters for GET /movies
+type MovieFilterQuery struct {
+	PaginationQuery
+	Genre      string `form:"genre"`
+	YearFrom   *int32 `form:"year_from" binding:"omitempty,min=1870,max=2100"`
+	YearTo     *int32 `form:"year_to" binding:"omitempty,min=1870,max=2100"`
+	RuntimeMin *int32 `form:"runtime_min" binding:"omitempty,min=0"`
+	RuntimeMax *int32 `form:"runtime_max" binding:"omitempty,min=0"`
+	Language   string `form:"language" binding:"omitempty,max=10"`
+	Country    string `form:"country" binding:"omitempty,max=100"`
+}
+
+// MovieSummaryResponse is the compact movie shape used in lists
+type MovieSummaryResponse struct {
+	ID              int32    `json:"id"`
+	Title           string   `json:"title"`
+	Slug            string   `json:"slug"`
+	PosterURL       *string  `json:"poster_url"`
+	ReleaseDate     *string  `json:"release_date"`
+	Runtime         *int32   `json:"runtime"`
+	UserAvgRating   float32  `json:"user_avg_rating"`
+	UserRatingCount int32    `json:"user_rating_count"`
+	ImdbRating      *float64 `json:"imdb_rating"`
+}
+
+// MovieDetailResponse is the full movie page payload
+type MovieDetailResponse struct {
+	MovieSummaryResponse
+	Overview         *string          `json:"overview"`
+	BackdropURL      *string          `json:"backdrop_url"`
+	TrailerURL       *string          `json:"trailer_url"`
+	ContentRating    *string          `json:"content_rating"`
+	OriginalLanguage *string          `json:"original_language"`
+	Country          *string          `json:"country"`
+	ImdbID           *string          `json:"imdb_id"`
+	RottenTomatoes   *int32           `json:"rotten_tomatoes"`
+	MetacriticScore  *int32           `json:"metacritic_score"`
+	LetterboxdRating *float64         `json:"letterboxd_rating"`
+	Genres           []GenreResponse  `json:"genres"`
+	Directors        []CreditResponse `json:"directors"`
+	Cast             []CreditResponse `json:"cast"`
+}
+
+// GenreResponse is a genre as exposed to clients
+type GenreResponse struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// CreditResponse is a person credited on a movie
+type CreditResponse struct {
+	PersonID  int32   `json:"person_id"`
+	Name      string  `json:"name"`
+	Slug      string  `json:"slug"`
+	PhotoURL  *string `json:"photo_url"`
+	Role      string  `json:"role"`
+	Character *string `json:"character,omitempty"`
+}
+
+// MovieRefResponse is the minimal movie shape embedded in other resources
+type MovieRefResponse struct {
+	ID          int32   `json:"id"`
+	Title       string  `json:"title"`
+	Slug        string  `json:"slug"`
+	PosterURL   *string `json:"poster_url"`
+	ReleaseDate *string `json:"release_date"`
+}
+
+// MovieStatsResponse carries the trigger-maintained community rating aggregate
+type MovieStatsResponse struct {
+	MovieID         int32   `json:"movie_id"`
+	UserAvgRating   float32 `json:"user_avg_rating"`
+	UserRatingCount int32   `json:"user_rating_count"`
+}
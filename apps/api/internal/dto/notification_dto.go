@@ -0,0 +1,55 @@
+package dto
+
+import "time"
+
+// NotificationMetadata is the structured payload stored with a notification so
+// clients can deep-link to the user, review or comment it is about
+type NotificationMetadata struct {
+	ActorID       int32  `json:"actor_id,omitempty"`
+	ActorUsername string `json:"actor_username,omitempty"`
+	MovieSlug     string `json:"movie_slug,omitempty"`
+	ReviewID      int32  `json:"review_id,omitempty"`
+	CommentID     int32  `json:"comment_id,omitempty"`
+	ParentID      int32  `json:"parent_id,omitempty"`
+	Reaction      string `json:"reaction,omitempty"`
+	Status        string `json:"status,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// NotificationsQuery is the query string of GET /me/notifications
+type NotificationsQuery struct {
+	PaginationQuery
+	Unread bool `form:"unread"`
+}
+
+type NotificationResponse struct {
+	ID        int32                 `json:"id"`
+	Type      string                `json:"type"`
+	Title     string                `json:"title"`
+	Content   *string               `json:"content"`
+	IsRead    bool                  `json:"is_read"`
+	Metadata  *NotificationMetadata `json:"metadata"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+// NotificationListResponse is a page of notifications, unread first, plus the total unread count
+type NotificationListResponse struct {
+	PaginatedResponse[NotificationResponse]
+	UnreadCount int64 `json:"unread_count"`
+}
+
+type UnreadCountResponse struct {
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// Event names sent on GET /notifications/stream
+const (
+	StreamEventNotification = "notification"
+	StreamEventUnreadCount  = "unread_count"
+)
+
+// StreamEvent is one server-sent event; Data is encoded as JSON
+type StreamEvent struct {
+	Event string
+	Data  any
+}
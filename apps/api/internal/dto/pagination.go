@@ -0,0 +1,65 @@
+package dto
+
+// PaginationQuery is the common page/page_size query string for list endpoints
+type PaginationQuery struct {
+	Page     int32 `form:"page,default=1" binding:"min=1"`
+	PageSize int32 `form:"page_size,default=20" binding:"min=1,max=100"`
+}
+
+// Limit returns the SQL LIMIT for the requested page
+func (p PaginationQuery) Limit() int32 {
+	return p.PageSize
+}
+
+// Offset returns the SQL OFFSET for the requested page
+func (p PaginationQuery) Offset() int32 {
+	return (p.Page - 1) * p.PageSize
+}
+
+// PageMeta describes where a page sits in the full result set
+type PageMeta struct {
+	Page     int32 `json:"page"`
+	PageSize int32 `json:"page_size"`
+	Total    int64 `json:"total"`
+}
+
+// PaginatedResponse wraps a page of items with its metadata
+type PaginatedResponse[T any] struct {
+	Items []T      `json:"items"`
+	Meta  PageMeta `json:"meta"`
+}
+
+// NewPaginatedResponse builds a response, always encoding items as an array
+func NewPaginatedResponse[T any](items []T, q PaginationQuery, total int64) PaginatedResponse[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return PaginatedResponse[T]{
+		Items: items,
+		Meta:  PageMeta{Page: q.Page, PageSize: q.PageSize, Total: total},
+	}
+}
+
+// CursorQuery is the query string for keyset-paginated endpoints
+type CursorQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int32  `form:"limit,default=20" binding:"min=1,max=50"`
+}
+
+// CursorPage is a page of items plus the opaque cursor for the next one (null on the last page)
+type CursorPage[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// NewCursorPage builds a cursor page, always encoding items as an array
+func NewCursorPage[T any](items []T, next string) CursorPage[T] {
+	if items == nil {
+		items = []T{}
+	}
+	page := CursorPage[T]{Items: items}
+	if next != "" {
+		page.NextCursor = &next
+	}
+	return page
+}
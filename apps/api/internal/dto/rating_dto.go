@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+// Sort orders accepted by GET /users/:username/ratings
+const (
+	RatingSortRecent    = "recent"
+	RatingSortScoreDesc = "score_desc"
+	RatingSortScoreAsc  = "score_asc"
+	RatingSortTitle     = "title"
+)
+
+// RateMovieRequest is the body of PUT /movies/:slug/rating
+type RateMovieRequest struct {
+	Score *int32 `json:"score" binding:"required,min=0,max=10"`
+}
+
+// UserRatingsQuery is the query string for listing a user's ratings
+type UserRatingsQuery struct {
+	PaginationQuery
+	Sort string `form:"sort,default=recent" binding:"oneof=recent score_desc score_asc title"`
+}
+
+// RatingResponse is returned after rating a movie, with the refreshed aggregate
+type RatingResponse struct {
+	Score      int32              `json:"score"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+	MovieStats MovieStatsResponse `json:"movie_stats"`
+}
+
+// UserRatingResponse is one entry of a user's rating list
+type UserRatingResponse struct {
+	Score     int32            `json:"score"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	Movie     MovieRefResponse `json:"movie"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+// ReactionTypes lists the reaction_type enum values in display order
+var ReactionTypes = []string{"LIKE", "LOVE", "LAUGH", "SAD", "ANGRY"}
+
+// SetReactionRequest is the body of PUT /reviews/:id/reaction and /comments/:id/reaction
+type SetReactionRequest struct {
+	Type string `json:"type" binding:"required,oneof=LIKE LOVE LAUGH SAD ANGRY"`
+}
+
+// ReactionSummary is the per-type breakdown of reactions on a review or comment
+type ReactionSummary struct {
+	Counts map[string]int64 `json:"counts"`
+	Total  int64            `json:"total"`
+	Mine   *string          `json:"mine"`
+}
+
+// NewReactionSummary returns an empty summary with every reaction type present
+func NewReactionSummary() ReactionSummary {
+	counts := make(map[string]int64, len(ReactionTypes))
+	for _, t := range ReactionTypes {
+		counts[t] = 0
+	}
+	return ReactionSummary{Counts: counts}
+}
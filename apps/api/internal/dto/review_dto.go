@@ -0,0 +1,49 @@
+package dto
+
+import "time"
+
+// Sort orders accepted by GET /movies/:slug/reviews
+const (
+	ReviewSortNewest    = "newest"
+	ReviewSortMostLiked = "most_liked"
+)
+
+// CreateReviewRequest is the body of POST /movies/:slug/reviews
+type CreateReviewRequest struct {
+	Title   string `json:"title" binding:"omitempty,max=255"`
+	Content string `json:"content" binding:"required,max=20000"`
+}
+
+// UpdateReviewRequest is the body of PATCH /reviews/:id; omitted fields are kept
+type UpdateReviewRequest struct {
+	Title   *string `json:"title" binding:"omitempty,max=255"`
+	Content *string `json:"content" binding:"omitempty,min=1,max=20000"`
+}
+
+// MovieReviewsQuery is the query string for listing a movie's reviews
+type MovieReviewsQuery struct {
+	PaginationQuery
+	Sort string `form:"sort,default=newest" binding:"oneof=newest most_liked"`
+}
+
+// AuthorResponse is the public identity of a content author
+type AuthorResponse struct {
+	ID          int32   `json:"id"`
+	Username    string  `json:"username"`
+	DisplayName string  `json:"display_name"`
+	AvatarURL   *string `json:"avatar_url"`
+}
+
+// ReviewResponse is a review with its author, the author's own rating and the movie
+type ReviewResponse struct {
+	ID           int32            `json:"id"`
+	Title        *string          `json:"title"`
+	Content      string           `json:"content"`
+	LikeCount    int32            `json:"like_count"`
+	AuthorRating *int32           `json:"author_rating"`
+	Author       AuthorResponse   `json:"author"`
+	Movie        MovieRefResponse `json:"movie"`
+	Reactions    ReactionSummary  `json:"reactions"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+// Search result types, also used as facet keys
+const (
+	SearchTypeAll    = "all"
+	SearchTypeMovie  = "movie"
+	SearchTypePerson = "person"
+)
+
+// Search modes: fuzzy matches whole titles and names despite typos, prefix
+// matches what has been typed so far for type-ahead
+const (
+	SearchModeFuzzy  = "fuzzy"
+	SearchModePrefix = "prefix"
+)
+
+// SearchQuery is the query string for GET /search. Thresholds below 0.1 would
+// match nearly every row, so they are rejected.
+type SearchQuery struct {
+	Q         string   `form:"q" binding:"required,min=2,max=100"`
+	Type      string   `form:"type,default=all" binding:"oneof=all movie person"`
+	Mode      string   `form:"mode,default=fuzzy" binding:"oneof=fuzzy prefix"`
+	Limit     int32    `form:"limit,default=10" binding:"min=1,max=50"`
+	Threshold *float64 `form:"threshold" binding:"omitempty,gte=0.1,lte=1"`
+}
+
+// SearchResult is a single ranked hit of any type
+type SearchResult struct {
+	Type     string  `json:"type"`
+	ID       int32   `json:"id"`
+	Name     string  `json:"name"`
+	Slug     string  `json:"slug"`
+	ImageURL *string `json:"image_url"`
+	Year     *int    `json:"year,omitempty"`
+	Score    float32 `json:"score"`
+}
+
+// SearchResponse holds the merged ranking plus per-type match counts
+type SearchResponse struct {
+	Query   string           `json:"query"`
+	Results []SearchResult   `json:"results"`
+	Facets  map[string]int64 `json:"facets"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+// ClientInfo identifies the device a session is opened or refreshed from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// SessionResponse is one signed-in device; the refresh token is never exposed
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  *string   `json:"user_agent"`
+	IPAddress  *string   `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+type RevokeSessionsResponse struct {
+	Revoked int64 `json:"revoked"`
+}
+
+// Security event types
+const (
+	SecurityEventRefreshTokenReuse = "REFRESH_TOKEN_REUSE"
+)
+
+type SecurityEventResponse struct {
+	ID        int32          `json:"id"`
+	Type      string         `json:"type"`
+	SessionID *string        `json:"session_id"`
+	IPAddress *string        `json:"ip_address"`
+	UserAgent *string        `json:"user_agent"`
+	Metadata  map[string]any `json:"metadata"`
+	CreatedAt time.Time      `json:"created_at"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // SignupRequest is what we expect from the frontend
 type SignupRequest struct {
     Email    string `json:"email" binding:"required,email"`
@@ -13,21 +15,95 @@ type LoginRequest struct {
     Password string `json:"password" binding:"required"`
 }
 
+// VerifyEmailRequest carries the token from the verification email
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest carries the token from the reset email and the new password
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6,max=72"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6,max=72"`
+}
+
 // UserResponse is what we send back (excluding sensitive data like password)
 type UserResponse struct {
-    ID          int32  `json:"id"`
-    Email       string `json:"email"`
-    Username    string `json:"username"`
-    DisplayName string `json:"display_name"`
+	ID               int32   `json:"id"`
+	Email            string  `json:"email"`
+	Username         string  `json:"username"`
+	DisplayName      string  `json:"display_name"`
+	AvatarURL        *string `json:"avatar_url"`
+	Bio              *string `json:"bio"`
+	IsVerified       bool    `json:"is_verified"`
+	ProfilePrivate   bool    `json:"profile_private"`
+	WatchlistPrivate bool    `json:"watchlist_private"`
 }
 
-// AuthResponse is the response for successful authentication
+// AuthResponse carries fresh tokens. In cookie auth mode the tokens are set as
+// cookies instead and only the CSRF token is returned.
 type AuthResponse struct {
-    AccessToken  string       `json:"access_token"`
-    RefreshToken string       `json:"refresh_token"`
-    User         UserResponse `json:"user"`
+	AccessToken  string       `json:"access_token,omitempty"`
+	RefreshToken string       `json:"refresh_token,omitempty"`
+	CSRFToken    string       `json:"csrf_token,omitempty"`
+	User         UserResponse `json:"user"`
 }
 
+// RefreshRequest may be omitted in cookie auth mode, the refresh cookie is used instead
 type RefreshRequest struct {
     RefreshToken string `json:"refresh_token" binding:"required"`
-}
\ No newline at end of file
+}
+
+// UpdateMeRequest is the body of PATCH /me; omitted fields are kept and an
+// empty string clears a text field
+type UpdateMeRequest struct {
+	DisplayName      *string `json:"display_name" binding:"omitempty,max=100"`
+	Bio              *string `json:"bio" binding:"omitempty,max=1000"`
+	AvatarURL        *string `json:"avatar_url" binding:"omitempty,max=2048"`
+	ProfilePrivate   *bool   `json:"profile_private"`
+	WatchlistPrivate *bool   `json:"watchlist_private"`
+}
+
+// RatingBucket is one bar of a rating histogram
+type RatingBucket struct {
+	Score int32 `json:"score"`
+	Count int64 `json:"count"`
+}
+
+// ProfileStats are the counters shown on a profile. WatchlistCount is null
+// when the watchlist is private to the viewer.
+type ProfileStats struct {
+	RatingCount     int64          `json:"rating_count"`
+	ReviewCount     int64          `json:"review_count"`
+	FollowerCount   int64          `json:"follower_count"`
+	FollowingCount  int64          `json:"following_count"`
+	WatchlistCount  *int64         `json:"watchlist_count"`
+	RatingHistogram []RatingBucket `json:"rating_histogram"`
+}
+
+// PublicProfileResponse is what anyone can see about a user (no email). Stats
+// is null when the profile is private and the viewer is not its owner.
+type PublicProfileResponse struct {
+	ID               int32         `json:"id"`
+	Username         string        `json:"username"`
+	DisplayName      string        `json:"display_name"`
+	AvatarURL        *string       `json:"avatar_url"`
+	Bio              *string       `json:"bio"`
+	JoinedAt         time.Time     `json:"joined_at"`
+	ProfilePrivate   bool          `json:"profile_private"`
+	WatchlistPrivate bool          `json:"watchlist_private"`
+	IsFollowing      bool          `json:"is_following"`
+	Stats            *ProfileStats `json:"stats"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+// AddToWatchlistRequest is the body of POST /movies/:slug/watchlist
+type AddToWatchlistRequest struct {
+	Notes string `json:"notes" binding:"omitempty,max=2000"`
+}
+
+// UpdateWatchlistItemRequest is the body of PATCH /me/watchlist/:id; an empty note clears it
+type UpdateWatchlistItemRequest struct {
+	Notes *string `json:"notes" binding:"required,max=2000"`
+}
+
+// MoveWatchlistItemRequest places an item between two neighbours. Either one
+// may be omitted to mean "directly after" or "directly before" the other, so
+// moving to the top only needs before_id set to the current first item.
+type MoveWatchlistItemRequest struct {
+	AfterID  *int32 `json:"after_id" binding:"omitempty,min=1"`
+	BeforeID *int32 `json:"before_id" binding:"omitempty,min=1"`
+}
+
+// WatchlistQuery is the query string of GET /me/watchlist
+type WatchlistQuery struct {
+	PaginationQuery
+	Watched *bool `form:"watched"`
+}
+
+// WatchlistItemResponse is one entry of a watchlist, in rank order
+type WatchlistItemResponse struct {
+	ID        int32            `json:"id"`
+	Notes     *string          `json:"notes"`
+	WatchedAt *time.Time       `json:"watched_at"`
+	AddedAt   time.Time        `json:"added_at"`
+	Movie     MovieRefResponse `json:"movie"`
+}
+
+// WatchedResponse is returned after marking an item watched. RatePrompt is set
+// when the user has not rated the movie yet, so clients can offer to rate it.
+type WatchedResponse struct {
+	Item       WatchlistItemResponse `json:"item"`
+	RatePrompt bool                  `json:"rate_prompt"`
+	Score      *int32                `json:"score"`
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	adminSvc *service.AdminService
+}
+
+func NewAdminHandler(as *service.AdminService) *AdminHandler {
+	return &AdminHandler{adminSvc: as}
+}
+
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.adminSvc.GetUser(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, "admin get user", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AdminHandler) Activate(c *gin.Context) { h.moderate(c, dto.ModerationActivate) }
+func (h *AdminHandler) Suspend(c *gin.Context)  { h.moderate(c, dto.ModerationSuspend) }
+func (h *AdminHandler) Ban(c *gin.Context)      { h.moderate(c, dto.ModerationBan) }
+func (h *AdminHandler) Unban(c *gin.Context)    { h.moderate(c, dto.ModerationUnban) }
+
+func (h *AdminHandler) StatusHistory(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var q dto.PaginationQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.adminSvc.StatusHistory(c.Request.Context(), id, q)
+	if err != nil {
+		h.writeError(c, "status history", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AdminHandler) SecurityEvents(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var q dto.PaginationQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.adminSvc.SecurityEvents(c.Request.Context(), id, q)
+	if err != nil {
+		h.writeError(c, "security events", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AdminHandler) ListSessions(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.adminSvc.ListSessions(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, "admin list sessions", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AdminHandler) RevokeSession(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.adminSvc.RevokeSession(c.Request.Context(), id, c.Param("sessionId")); err != nil {
+		h.writeError(c, "admin revoke session", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AdminHandler) moderate(c *gin.Context, action string) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.ModerationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID := currentUser(c).UserID
+	resp, err := h.adminSvc.Moderate(c.Request.Context(), adminID, id, action, req.Reason)
+	if err != nil {
+		h.writeError(c, action+" user", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *AdminHandler) writeError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrReasonRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidStatusTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCannotModerateSelf), errors.Is(err, service.ErrCannotModerateAdmin):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s error: %v", op, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"net/url"
 
 	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
 	"github.com/MassoudJavadi/filmophilia/api/internal/mapper"
+	"github.com/MassoudJavadi/filmophilia/api/internal/middleware"
 	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/oauth"
 	"github.com/MassoudJavadi/filmophilia/api/internal/service"
 	"github.com/gin-gonic/gin"
@@ -15,11 +17,12 @@ import (
 type AuthHandler struct {
 	authSvc  *service.AuthService
 	oauthSvc *service.OAuthService
+	cookies  CookieConfig
 }
 
 
-func NewAuthHandler(as *service.AuthService, os *service.OAuthService) *AuthHandler {
-	return &AuthHandler{authSvc: as, oauthSvc: os}
+func NewAuthHandler(as *service.AuthService, os *service.OAuthService, cookies CookieConfig) *AuthHandler {
+	return &AuthHandler{authSvc: as, oauthSvc: os, cookies: cookies}
 }
 
 
@@ -53,57 +56,197 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authSvc.Login(c.Request.Context(), req)
+	resp, err := h.authSvc.Login(c.Request.Context(), req, clientInfo(c))
 	if err != nil {
-		if errors.Is(err, service.ErrInvalidCredentials) || errors.Is(err, service.ErrUserBanned) {
+		if errors.Is(err, service.ErrInvalidCredentials) ||
+			errors.Is(err, service.ErrUserBanned) ||
+			errors.Is(err, service.ErrUserSuspended) {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
 		}
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		log.Printf("login error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
-	c.JSON(http.StatusOK, resp)
+	h.writeTokens(c, "login", resp)
 }
 
 func (h *AuthHandler) Refresh(c *gin.Context) {
-	var req dto.RefreshRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	refreshToken, ok := h.refreshToken(c)
+	if !ok {
 		return
 	}
 
-	resp, err := h.authSvc.Refresh(c.Request.Context(), req.RefreshToken)
+	resp, err := h.authSvc.Refresh(c.Request.Context(), refreshToken, clientInfo(c))
 	if err != nil {
-		if errors.Is(err, service.ErrInvalidToken) {
+		if errors.Is(err, service.ErrInvalidToken) ||
+			errors.Is(err, service.ErrUserBanned) ||
+			errors.Is(err, service.ErrUserSuspended) {
+			// Stop the browser from sending a token that will never work again
+			h.cookies.clearTokens(c)
 			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
 		}
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		log.Printf("refresh error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
-	c.JSON(http.StatusOK, resp)
+	h.writeTokens(c, "refresh", resp)
+}
+
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.authSvc.VerifyEmail(c.Request.Context(), req.Token)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidVerifyToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("verify email error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, mapper.ToUserResponse(user))
+}
+
+// ResendVerification answers the same way whether or not the email is registered
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var req dto.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authSvc.ResendVerification(c.Request.Context(), req.Email); err != nil {
+		log.Printf("resend verification error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "if an unverified account uses this email, a new verification link has been sent"})
+}
+
+// ForgotPassword answers the same way whether or not the email is registered
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.authSvc.ForgotPassword(c.Request.Context(), req.Email)
+	c.JSON(http.StatusAccepted, gin.H{"message": "if an account uses this email, a password reset link has been sent"})
+}
+
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authSvc.ResetPassword(c.Request.Context(), req); err != nil {
+		if errors.Is(err, service.ErrInvalidResetToken) || errors.Is(err, service.ErrPasswordTooLong) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("reset password error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "password has been reset, please log in again"})
+}
+
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := currentUser(c).UserID
+	resp, err := h.authSvc.ChangePassword(c.Request.Context(), userID, req, clientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPasswordTooLong):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrIncorrectPassword):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrNoPassword):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			log.Printf("change password error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+	h.writeTokens(c, "change password", resp)
 }
 
 func (h *AuthHandler) Logout(c *gin.Context) {
-    var req dto.RefreshRequest //Get refresh token from body to invalidate it.
-    if err := c.ShouldBindJSON(&req); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "refresh token required"})
+    refreshToken, ok := h.refreshToken(c) //Get refresh token to invalidate it.
+    if !ok {
         return
     }
 
-    if err := h.authSvc.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+    if err := h.authSvc.Logout(c.Request.Context(), refreshToken); err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to logout"})
         return
     }
 
+    h.cookies.clearTokens(c)
     c.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})
 }
 
+// refreshToken reads the refresh token from the refresh cookie in cookie mode,
+// after the CSRF check, and from the body otherwise. It writes the error
+// response and returns false when there is none.
+func (h *AuthHandler) refreshToken(c *gin.Context) (string, bool) {
+	if h.cookies.Enabled {
+		if t, err := c.Cookie(middleware.RefreshTokenCookie); err == nil && t != "" {
+			if !middleware.ValidCSRF(c) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "missing or invalid csrf token"})
+				return "", false
+			}
+			return t, true
+		}
+	}
+
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "refresh token required"})
+		return "", false
+	}
+	return req.RefreshToken, true
+}
+
+// writeTokens answers 200 with fresh tokens, moving them into cookies in cookie mode
+func (h *AuthHandler) writeTokens(c *gin.Context, op string, resp *dto.AuthResponse) {
+	if err := h.cookies.writeTokens(c, resp); err != nil {
+		log.Printf("%s error: %v", op, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
 func (h *AuthHandler) GetMe(c *gin.Context) {
 
-    userID := c.MustGet("user_id").(int32)
+    userID := currentUser(c).UserID
     
 
     user, err := h.authSvc.GetUser(c.Request.Context(), userID)
@@ -115,46 +258,186 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
     c.JSON(http.StatusOK, mapper.ToUserResponse(user))
 }
 
-func (h *AuthHandler) GoogleRedirect(c *gin.Context) {
-	state, err := oauth.GenerateState(32)
+// ProviderRedirect starts the sign-in flow of an enabled provider.
+// ?return_to= is where the frontend should land afterwards: a path, or a URL
+// on a whitelisted origin. Linking starts at POST /me/accounts/:provider/link.
+func (h *AuthHandler) ProviderRedirect(c *gin.Context) {
+	flow, authURL, err := h.oauthSvc.StartFlow(c.Request.Context(), c.Param("provider"), c.Query("return_to"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate state"})
+		h.writeOAuthError(c, "oauth redirect", err)
 		return
 	}
 
+	// A link flow abandoned earlier must not turn this sign-in into a link
+	c.SetCookie("oauth_link", "", -1, "/", "", false, true)
+
 	// ذخیره استیت در کوکی برای ۱۵ دقیقه
 	// Domain رو اگه روی لوکال هستی خالی بذار یا localhost بذار
-	c.SetCookie("oauth_state", state, 900, "/", "", false, true)
+	// The PKCE verifier, nonce and return_to travel with the state and never reach the provider
+	c.SetCookie("oauth_state", flow.Encode(), 900, "/", "", false, true)
 
-	url := h.oauthSvc.GetGoogleAuthURL(state)
-	c.Redirect(http.StatusTemporaryRedirect, url)
+	c.Redirect(http.StatusTemporaryRedirect, authURL)
 }
 
-func (h *AuthHandler) GoogleCallback(c *gin.Context) {
+// ProviderCallback sends the browser back to the frontend callback page with
+// ?code= to trade at POST /auth/exchange, ?linked=<provider> after a link, or
+// ?error= when something went wrong
+func (h *AuthHandler) ProviderCallback(c *gin.Context) {
 
 	cookieState, err := c.Cookie("oauth_state")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "state cookie not found"})
+		h.finishOAuth(c, url.Values{"error": {"state cookie not found"}})
+		return
+	}
+	flow, err := oauth.DecodeFlow(cookieState)
+	if err != nil {
+		h.finishOAuth(c, url.Values{"error": {"invalid state cookie"}})
 		return
 	}
 
 	queryState := c.Query("state")
 
-	
-	if cookieState != queryState {
-		c.JSON(http.StatusForbidden, gin.H{"error": "invalid oauth state (CSRF detected!)"})
+	if flow.State != queryState || flow.Provider != c.Param("provider") {
+		h.finishOAuth(c, url.Values{"error": {"invalid oauth state"}})
 		return
 	}
 
-    //Remove cookie after use
+	//Remove cookie after use
 	c.SetCookie("oauth_state", "", -1, "/", "", false, true)
 
+	// The user declined, or the provider refused the request
+	if e := c.Query("error"); e != "" {
+		h.finishOAuth(c, url.Values{"error": {"authorization failed: " + e}})
+		return
+	}
 	code := c.Query("code")
-	resp, err := h.oauthSvc.HandleGoogleCallback(c.Request.Context(), code)
+
+	if link, err := c.Cookie("oauth_link"); err == nil {
+		c.SetCookie("oauth_link", "", -1, "/", "", false, true)
+		userID, err := h.oauthSvc.VerifyLinkToken(link)
+		if err != nil {
+			h.finishOAuthError(c, "link account", err)
+			return
+		}
+		if _, err := h.oauthSvc.LinkCallback(c.Request.Context(), userID, code, flow); err != nil {
+			h.finishOAuthError(c, "link account", err)
+			return
+		}
+		q := url.Values{"linked": {flow.Provider}}
+		if flow.ReturnTo != "" {
+			q.Set("return_to", flow.ReturnTo)
+		}
+		h.finishOAuth(c, q)
+		return
+	}
+
+	exchangeCode, err := h.oauthSvc.SignInCallback(c.Request.Context(), code, flow)
+	if err != nil {
+		h.finishOAuthError(c, "oauth callback", err)
+		return
+	}
+
+	h.finishOAuth(c, url.Values{"code": {exchangeCode}})
+}
+
+// Exchange trades the one-time code from the provider callback for tokens
+func (h *AuthHandler) Exchange(c *gin.Context) {
+	var req dto.ExchangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.oauthSvc.Exchange(c.Request.Context(), req.Code, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.writeOAuthError(c, "oauth exchange", err)
 		return
 	}
+	if err := h.cookies.writeTokens(c, &resp.AuthResponse); err != nil {
+		h.writeOAuthError(c, "oauth exchange", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
 
+func (h *AuthHandler) finishOAuth(c *gin.Context, params url.Values) {
+	// Keep the one-time code out of Referer headers sent by the frontend page
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Redirect(http.StatusFound, h.oauthSvc.FrontendURL(params))
+}
+
+func (h *AuthHandler) finishOAuthError(c *gin.Context, op string, err error) {
+	_, msg := oauthError(op, err)
+	h.finishOAuth(c, url.Values{"error": {msg}})
+}
+
+func (h *AuthHandler) ListAccounts(c *gin.Context) {
+	userID := currentUser(c).UserID
+	resp, err := h.oauthSvc.ListAccounts(c.Request.Context(), userID)
+	if err != nil {
+		h.writeOAuthError(c, "list accounts", err)
+		return
+	}
 	c.JSON(http.StatusOK, resp)
-}
\ No newline at end of file
+}
+
+// LinkAccount starts linking a provider and returns the provider URL the
+// browser navigates to. The flow and link cookies are set on this
+// authenticated response, so the callback only links for the browser that
+// asked; ?return_to= works as in ProviderRedirect.
+func (h *AuthHandler) LinkAccount(c *gin.Context) {
+	userID := currentUser(c).UserID
+	flow, authURL, link, err := h.oauthSvc.StartLink(c.Request.Context(), userID, c.Param("provider"), c.Query("return_to"))
+	if err != nil {
+		h.writeOAuthError(c, "link account", err)
+		return
+	}
+
+	c.SetCookie("oauth_link", link, 900, "/", "", false, true)
+	c.SetCookie("oauth_state", flow.Encode(), 900, "/", "", false, true)
+
+	c.JSON(http.StatusOK, dto.LinkURLResponse{URL: authURL})
+}
+
+func (h *AuthHandler) UnlinkAccount(c *gin.Context) {
+	userID := currentUser(c).UserID
+	if err := h.oauthSvc.Unlink(c.Request.Context(), userID, c.Param("provider")); err != nil {
+		h.writeOAuthError(c, "unlink account", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) writeOAuthError(c *gin.Context, op string, err error) {
+	status, msg := oauthError(op, err)
+	c.JSON(status, gin.H{"error": msg})
+}
+
+// oauthError maps an OAuth error to a status and a message safe to show the user
+func oauthError(op string, err error) (int, string) {
+	switch {
+	case errors.Is(err, service.ErrUserBanned), errors.Is(err, service.ErrUserSuspended):
+		return http.StatusUnauthorized, err.Error()
+	case errors.Is(err, service.ErrOAuthEmailUnverified), errors.Is(err, service.ErrEmailNotVerified):
+		return http.StatusForbidden, err.Error()
+	case errors.Is(err, service.ErrAccountLinkedElsewhere),
+		errors.Is(err, service.ErrProviderAlreadyLinked),
+		errors.Is(err, service.ErrLastSignInMethod):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, service.ErrInvalidLinkToken),
+		errors.Is(err, service.ErrInvalidExchangeCode),
+		errors.Is(err, service.ErrInvalidReturnTo):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, service.ErrProviderRejected):
+		// The detail may echo provider responses, so it only goes to the log
+		log.Printf("%s error: %v", op, err)
+		return http.StatusUnauthorized, service.ErrProviderRejected.Error()
+	case errors.Is(err, service.ErrUnknownProvider),
+		errors.Is(err, service.ErrAccountNotLinked),
+		errors.Is(err, service.ErrUserNotFound):
+		return http.StatusNotFound, err.Error()
+	default:
+		log.Printf("%s error: %v", op, err)
+		return http.StatusInternalServerError, "internal server error"
+	}
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	commentSvc *service.CommentService
+}
+
+func NewCommentHandler(cs *service.CommentService) *CommentHandler {
+	return &CommentHandler{commentSvc: cs}
+}
+
+func (h *CommentHandler) Create(c *gin.Context) {
+	var req dto.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := currentUser(c).UserID
+	resp, err := h.commentSvc.Create(c.Request.Context(), userID, c.Param("slug"), req)
+	if err != nil {
+		h.writeError(c, "create comment", err)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *CommentHandler) Update(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := currentUser(c).UserID
+	resp, err := h.commentSvc.Update(c.Request.Context(), userID, id, req.Content)
+	if err != nil {
+		h.writeError(c, "update comment", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *CommentHandler) Delete(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := currentUser(c).UserID
+	if err := h.commentSvc.Delete(c.Request.Context(), userID, id); err != nil {
+		h.writeError(c, "delete comment", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CommentHandler) ListByMovie(c *gin.Context) {
+	var q dto.CommentTreeQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.commentSvc.ListByMovie(c.Request.Context(), c.Param("slug"), q, viewerID(c))
+	if err != nil {
+		h.writeError(c, "list comments", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *CommentHandler) Thread(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var q dto.CommentTreeQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.commentSvc.Thread(c.Request.Context(), id, q.Depth, viewerID(c))
+	if err != nil {
+		h.writeError(c, "get comment thread", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *CommentHandler) writeError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrMovieNotFound), errors.Is(err, service.ErrCommentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidParent), errors.Is(err, service.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrNotCommentOwner):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s error: %v", op, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
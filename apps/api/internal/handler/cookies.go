@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/middleware"
+	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/token"
+	"github.com/gin-gonic/gin"
+)
+
+// The refresh cookie is only sent to the auth endpoints that read it
+const refreshCookiePath = "/api/v1/auth"
+
+// CookieConfig switches the token endpoints to cookie mode: tokens are set as
+// HttpOnly cookies, out of reach of page scripts, and left out of response
+// bodies. Requests authenticated by cookie must pass the CSRF check.
+type CookieConfig struct {
+	Enabled  bool
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+}
+
+// writeTokens sends freshly issued tokens to the client: in the body, or in
+// cookie mode as cookies with only the CSRF token in the body
+func (cfg CookieConfig) writeTokens(c *gin.Context, resp *dto.AuthResponse) error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	// An existing CSRF token is kept, so other tabs holding it keep working
+	csrf, err := c.Cookie(middleware.CSRFCookie)
+	if err != nil || csrf == "" {
+		if csrf, err = newCSRFToken(); err != nil {
+			return err
+		}
+	}
+
+	cfg.set(c, middleware.AccessTokenCookie, resp.AccessToken, "/", token.AccessTokenDuration, true)
+	cfg.set(c, middleware.RefreshTokenCookie, resp.RefreshToken, refreshCookiePath, token.RefreshTokenDuration, true)
+	cfg.set(c, middleware.CSRFCookie, csrf, "/", token.RefreshTokenDuration, false)
+
+	resp.AccessToken = ""
+	resp.RefreshToken = ""
+	resp.CSRFToken = csrf
+	return nil
+}
+
+func (cfg CookieConfig) clearTokens(c *gin.Context) {
+	if !cfg.Enabled {
+		return
+	}
+	cfg.set(c, middleware.AccessTokenCookie, "", "/", -1, true)
+	cfg.set(c, middleware.RefreshTokenCookie, "", refreshCookiePath, -1, true)
+	cfg.set(c, middleware.CSRFCookie, "", "/", -1, false)
+}
+
+// set writes a cookie; a negative maxAge deletes it
+func (cfg CookieConfig) set(c *gin.Context, name, value, path string, maxAge time.Duration, httpOnly bool) {
+	seconds := int(maxAge / time.Second)
+	if maxAge < 0 {
+		seconds = -1
+	}
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   cfg.Domain,
+		MaxAge:   seconds,
+		Secure:   cfg.Secure,
+		HttpOnly: httpOnly,
+		SameSite: cfg.SameSite,
+	})
+}
+
+func newCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
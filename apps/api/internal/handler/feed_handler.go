@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type FeedHandler struct {
+	feedSvc *service.FeedService
+}
+
+func NewFeedHandler(fs *service.FeedService) *FeedHandler {
+	return &FeedHandler{feedSvc: fs}
+}
+
+func (h *FeedHandler) Get(c *gin.Context) {
+	var q dto.CursorQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := currentUser(c).UserID
+	resp, err := h.feedSvc.Feed(c.Request.Context(), userID, q)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("get feed error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type FollowHandler struct {
+	followSvc *service.FollowService
+}
+
+func NewFollowHandler(fs *service.FollowService) *FollowHandler {
+	return &FollowHandler{followSvc: fs}
+}
+
+func (h *FollowHandler) Follow(c *gin.Context) {
+	userID := currentUser(c).UserID
+	resp, err := h.followSvc.Follow(c.Request.Context(), userID, c.Param("username"))
+	if err != nil {
+		h.writeError(c, "follow", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *FollowHandler) Unfollow(c *gin.Context) {
+	userID := currentUser(c).UserID
+	resp, err := h.followSvc.Unfollow(c.Request.Context(), userID, c.Param("username"))
+	if err != nil {
+		h.writeError(c, "unfollow", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *FollowHandler) Followers(c *gin.Context) {
+	var q dto.PaginationQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.followSvc.ListFollowers(c.Request.Context(), c.Param("username"), q, viewerID(c))
+	if err != nil {
+		h.writeError(c, "list followers", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *FollowHandler) Following(c *gin.Context) {
+	var q dto.PaginationQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.followSvc.ListFollowing(c.Request.Context(), c.Param("username"), q, viewerID(c))
+	if err != nil {
+		h.writeError(c, "list following", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *FollowHandler) Mutuals(c *gin.Context) {
+	var q dto.PaginationQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.followSvc.ListMutuals(c.Request.Context(), c.Param("username"), q, viewerID(c))
+	if err != nil {
+		h.writeError(c, "list mutuals", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *FollowHandler) writeError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrProfilePrivate):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCannotFollowSelf):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s error: %v", op, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/token"
+	"github.com/gin-gonic/gin"
+)
+
+// KeysHandler publishes the public keys access tokens are signed with, so
+// other services can verify them without sharing a secret
+type KeysHandler struct {
+	jwt *token.JWTManager
+}
+
+func NewKeysHandler(jwt *token.JWTManager) *KeysHandler {
+	return &KeysHandler{jwt: jwt}
+}
+
+// JWKS serves GET /.well-known/jwks.json. Verifiers should refetch it when
+// they meet an unknown kid; retired keys stay listed until their tokens expire.
+func (h *KeysHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{"keys": h.jwt.PublicKeys()})
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type MovieHandler struct {
+	movieSvc *service.MovieService
+}
+
+func NewMovieHandler(ms *service.MovieService) *MovieHandler {
+	return &MovieHandler{movieSvc: ms}
+}
+
+func (h *MovieHandler) List(c *gin.Context) {
+	var q dto.MovieFilterQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.movieSvc.ListMovies(c.Request.Context(), q)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("list movies error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *MovieHandler) GetBySlug(c *gin.Context) {
+	resp, err := h.movieSvc.GetMovieBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, service.ErrMovieNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("get movie error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *MovieHandler) ListGenres(c *gin.Context) {
+	genres, err := h.movieSvc.ListGenres(c.Request.Context())
+	if err != nil {
+		log.Printf("list genres error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, genres)
+}
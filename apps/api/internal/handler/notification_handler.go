@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// streamHeartbeat keeps idle streams alive through proxies that drop silent connections
+const streamHeartbeat = 25 * time.Second
+
+type NotificationHandler struct {
+	notificationSvc *service.NotificationService
+	hub             *service.NotificationHub
+	sessions        *service.SessionValidator
+}
+
+func NewNotificationHandler(ns *service.NotificationService, hub *service.NotificationHub, sessions *service.SessionValidator) *NotificationHandler {
+	return &NotificationHandler{notificationSvc: ns, hub: hub, sessions: sessions}
+}
+
+func (h *NotificationHandler) List(c *gin.Context) {
+	var q dto.NotificationsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := currentUser(c).UserID
+	resp, err := h.notificationSvc.List(c.Request.Context(), userID, q)
+	if err != nil {
+		h.writeError(c, "list notifications", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	userID := currentUser(c).UserID
+	count, err := h.notificationSvc.UnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		h.writeError(c, "unread count", err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.UnreadCountResponse{UnreadCount: count})
+}
+
+// Stream is a server-sent event stream of new notifications and unread count
+// changes. It starts with the current unread count and ends when the client
+// goes away, its session is revoked or the server shuts down. The session is
+// checked again on every heartbeat, since a stream outlives the request that
+// authenticated it. EventSource cannot send headers, so browsers authenticate
+// it with the access cookie in cookie auth mode.
+func (h *NotificationHandler) Stream(c *gin.Context) {
+	p := currentUser(c)
+	userID := p.UserID
+
+	count, err := h.notificationSvc.UnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		h.writeError(c, "notification stream", err)
+		return
+	}
+
+	sub, ok := h.hub.Subscribe(userID)
+	if !ok {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "server is shutting down"})
+		return
+	}
+	defer h.hub.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent(dto.StreamEventUnreadCount, dto.UnreadCountResponse{UnreadCount: count})
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case ev, ok := <-sub.Events():
+			if !ok {
+				return false
+			}
+			c.SSEvent(ev.Event, ev.Data)
+			return true
+		case <-heartbeat.C:
+			active, err := h.sessions.SessionActive(c.Request.Context(), userID, p.SessionID)
+			if err != nil {
+				log.Printf("notification stream error: %v", err)
+				return false
+			}
+			if !active {
+				// Signed out, revoked or banned; reconnecting will get a 401
+				return false
+			}
+			_, err = io.WriteString(w, ": ping\n\n")
+			return err == nil
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := currentUser(c).UserID
+	if err := h.notificationSvc.MarkRead(c.Request.Context(), userID, id); err != nil {
+		h.writeError(c, "mark notification read", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID := currentUser(c).UserID
+	n, err := h.notificationSvc.MarkAllRead(c.Request.Context(), userID)
+	if err != nil {
+		h.writeError(c, "mark all notifications read", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"marked": n})
+}
+
+func (h *NotificationHandler) Delete(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := currentUser(c).UserID
+	if err := h.notificationSvc.Delete(c.Request.Context(), userID, id); err != nil {
+		h.writeError(c, "delete notification", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *NotificationHandler) writeError(c *gin.Context, op string, err error) {
+	if errors.Is(err, service.ErrNotificationNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	log.Printf("%s error: %v", op, err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+}
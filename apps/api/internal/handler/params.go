@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// paramID parses a numeric path parameter, writing a 400 response when it is malformed
+func paramID(c *gin.Context, name string) (int32, bool) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 32)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return 0, false
+	}
+	return int32(id), true
+}
+
+// currentUser returns the caller on routes behind AuthMiddleware
+func currentUser(c *gin.Context) middleware.Principal {
+	p, _ := middleware.PrincipalFrom(c)
+	return p
+}
+
+// viewerID returns the authenticated caller on optionally-authenticated routes, or 0 for anonymous
+func viewerID(c *gin.Context) int32 {
+	if p, ok := middleware.PrincipalFrom(c); ok {
+		return p.UserID
+	}
+	return 0
+}
+
+// Longest user agent stored with a session; anything beyond is client noise
+const maxUserAgentLen = 512
+
+// clientInfo describes the requesting device for the session list
+func clientInfo(c *gin.Context) dto.ClientInfo {
+	// Postgres refuses invalid UTF-8, and a header may carry any bytes
+	ua := strings.ToValidUTF8(c.Request.UserAgent(), "")
+	if len(ua) > maxUserAgentLen {
+		// Cut on a rune boundary so the stored value stays valid too
+		cut := maxUserAgentLen
+		for cut > 0 && !utf8.RuneStart(ua[cut]) {
+			cut--
+		}
+		ua = ua[:cut]
+	}
+	return dto.ClientInfo{UserAgent: ua, IPAddress: c.ClientIP()}
+}
+
+// sessionID returns the session the caller's access token was issued with, or "" for older tokens
+func sessionID(c *gin.Context) string {
+	return currentUser(c).SessionID
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestClientInfoUserAgent(t *testing.T) {
+	tests := []struct {
+		name string
+		ua   string
+		want string
+	}{
+		{"short", "Mozilla/5.0", "Mozilla/5.0"},
+		{"exactly the limit", strings.Repeat("a", maxUserAgentLen), strings.Repeat("a", maxUserAgentLen)},
+		{"ascii over the limit", strings.Repeat("a", maxUserAgentLen+10), strings.Repeat("a", maxUserAgentLen)},
+		// 511 bytes of ASCII then a 3-byte rune straddling the limit
+		{"rune across the limit", strings.Repeat("a", maxUserAgentLen-1) + "€€", strings.Repeat("a", maxUserAgentLen-1)},
+		{"multi-byte only", strings.Repeat("é", maxUserAgentLen), strings.Repeat("é", maxUserAgentLen/2)},
+		{"invalid bytes are dropped", "Mozilla\xff/5.0", "Mozilla/5.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			c.Request.Header.Set("User-Agent", tt.ua)
+
+			got := clientInfo(c).UserAgent
+			if got != tt.want {
+				t.Errorf("user agent = %q (%d bytes), want %q (%d bytes)", got, len(got), tt.want, len(tt.want))
+			}
+			if !utf8.ValidString(got) || len(got) > maxUserAgentLen {
+				t.Errorf("user agent is %d bytes, valid UTF-8 %v", len(got), utf8.ValidString(got))
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type RatingHandler struct {
+	ratingSvc *service.RatingService
+}
+
+func NewRatingHandler(rs *service.RatingService) *RatingHandler {
+	return &RatingHandler{ratingSvc: rs}
+}
+
+func (h *RatingHandler) Rate(c *gin.Context) {
+	var req dto.RateMovieRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := currentUser(c).UserID
+	resp, err := h.ratingSvc.RateMovie(c.Request.Context(), userID, c.Param("slug"), *req.Score)
+	if err != nil {
+		if errors.Is(err, service.ErrMovieNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("rate movie error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *RatingHandler) Delete(c *gin.Context) {
+	userID := currentUser(c).UserID
+	stats, err := h.ratingSvc.DeleteRating(c.Request.Context(), userID, c.Param("slug"))
+	if err != nil {
+		if errors.Is(err, service.ErrMovieNotFound) || errors.Is(err, service.ErrRatingNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("delete rating error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"movie_stats": stats})
+}
+
+func (h *RatingHandler) ListByUser(c *gin.Context) {
+	var q dto.UserRatingsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.ratingSvc.ListUserRatings(c.Request.Context(), c.Param("username"), q, viewerID(c))
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrProfilePrivate) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("list user ratings error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ReactionHandler struct {
+	reactionSvc *service.ReactionService
+}
+
+func NewReactionHandler(rs *service.ReactionService) *ReactionHandler {
+	return &ReactionHandler{reactionSvc: rs}
+}
+
+func (h *ReactionHandler) SetOnReview(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.SetReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := currentUser(c).UserID
+	resp, err := h.reactionSvc.ReactToReview(c.Request.Context(), userID, id, req.Type)
+	if err != nil {
+		h.writeError(c, "react to review", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ReactionHandler) ClearOnReview(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := currentUser(c).UserID
+	resp, err := h.reactionSvc.ClearReviewReaction(c.Request.Context(), userID, id)
+	if err != nil {
+		h.writeError(c, "clear review reaction", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ReactionHandler) SetOnComment(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.SetReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := currentUser(c).UserID
+	resp, err := h.reactionSvc.ReactToComment(c.Request.Context(), userID, id, req.Type)
+	if err != nil {
+		h.writeError(c, "react to comment", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ReactionHandler) ClearOnComment(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := currentUser(c).UserID
+	resp, err := h.reactionSvc.ClearCommentReaction(c.Request.Context(), userID, id)
+	if err != nil {
+		h.writeError(c, "clear comment reaction", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ReactionHandler) writeError(c *gin.Context, op string, err error) {
+	if errors.Is(err, service.ErrReviewNotFound) || errors.Is(err, service.ErrCommentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	log.Printf("%s error: %v", op, err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewHandler struct {
+	reviewSvc *service.ReviewService
+}
+
+func NewReviewHandler(rs *service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{reviewSvc: rs}
+}
+
+func (h *ReviewHandler) Create(c *gin.Context) {
+	var req dto.CreateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := currentUser(c).UserID
+	resp, err := h.reviewSvc.Create(c.Request.Context(), userID, c.Param("slug"), req)
+	if err != nil {
+		h.writeError(c, "create review", err)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *ReviewHandler) Get(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.reviewSvc.Get(c.Request.Context(), id, viewerID(c))
+	if err != nil {
+		h.writeError(c, "get review", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ReviewHandler) Update(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := currentUser(c).UserID
+	resp, err := h.reviewSvc.Update(c.Request.Context(), userID, id, req)
+	if err != nil {
+		h.writeError(c, "update review", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ReviewHandler) Delete(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := currentUser(c).UserID
+	if err := h.reviewSvc.Delete(c.Request.Context(), userID, id); err != nil {
+		h.writeError(c, "delete review", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ReviewHandler) ListByMovie(c *gin.Context) {
+	var q dto.MovieReviewsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.reviewSvc.ListByMovie(c.Request.Context(), c.Param("slug"), q, viewerID(c))
+	if err != nil {
+		h.writeError(c, "list movie reviews", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ReviewHandler) ListByUser(c *gin.Context) {
+	var q dto.PaginationQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.reviewSvc.ListByUser(c.Request.Context(), c.Param("username"), q, viewerID(c))
+	if err != nil {
+		h.writeError(c, "list user reviews", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ReviewHandler) writeError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrMovieNotFound),
+		errors.Is(err, service.ErrUserNotFound),
+		errors.Is(err, service.ErrReviewNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrReviewExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrNotReviewOwner), errors.Is(err, service.ErrProfilePrivate):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s error: %v", op, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	searchSvc *service.SearchService
+}
+
+func NewSearchHandler(ss *service.SearchService) *SearchHandler {
+	return &SearchHandler{searchSvc: ss}
+}
+
+func (h *SearchHandler) Search(c *gin.Context) {
+	var q dto.SearchQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.searchSvc.Search(c.Request.Context(), q)
+	if err != nil {
+		log.Printf("search error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler struct {
+	sessionSvc *service.SessionService
+}
+
+func NewSessionHandler(ss *service.SessionService) *SessionHandler {
+	return &SessionHandler{sessionSvc: ss}
+}
+
+func (h *SessionHandler) List(c *gin.Context) {
+	userID := currentUser(c).UserID
+	resp, err := h.sessionSvc.List(c.Request.Context(), userID, sessionID(c))
+	if err != nil {
+		h.writeError(c, "list sessions", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	userID := currentUser(c).UserID
+	if err := h.sessionSvc.Revoke(c.Request.Context(), userID, c.Param("id")); err != nil {
+		h.writeError(c, "revoke session", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeOthers is "log out everywhere else"
+func (h *SessionHandler) RevokeOthers(c *gin.Context) {
+	userID := currentUser(c).UserID
+	n, err := h.sessionSvc.RevokeOthers(c.Request.Context(), userID, sessionID(c))
+	if err != nil {
+		h.writeError(c, "revoke other sessions", err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.RevokeSessionsResponse{Revoked: n})
+}
+
+func (h *SessionHandler) writeError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSessionUnknown):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s error: %v", op, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
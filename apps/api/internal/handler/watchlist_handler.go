@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type WatchlistHandler struct {
+	watchlistSvc *service.WatchlistService
+}
+
+func NewWatchlistHandler(ws *service.WatchlistService) *WatchlistHandler {
+	return &WatchlistHandler{watchlistSvc: ws}
+}
+
+func (h *WatchlistHandler) List(c *gin.Context) {
+	var q dto.WatchlistQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := currentUser(c).UserID
+	resp, err := h.watchlistSvc.List(c.Request.Context(), userID, q)
+	if err != nil {
+		h.writeError(c, "list watchlist", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *WatchlistHandler) ListByUser(c *gin.Context) {
+	var q dto.WatchlistQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.watchlistSvc.ListByUser(c.Request.Context(), c.Param("username"), q, viewerID(c))
+	if err != nil {
+		h.writeError(c, "list user watchlist", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *WatchlistHandler) Add(c *gin.Context) {
+	var req dto.AddToWatchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := currentUser(c).UserID
+	resp, err := h.watchlistSvc.Add(c.Request.Context(), userID, c.Param("slug"), req)
+	if err != nil {
+		h.writeError(c, "add to watchlist", err)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *WatchlistHandler) Update(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateWatchlistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := currentUser(c).UserID
+	resp, err := h.watchlistSvc.UpdateNotes(c.Request.Context(), userID, id, req)
+	if err != nil {
+		h.writeError(c, "update watchlist item", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *WatchlistHandler) Remove(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := currentUser(c).UserID
+	if err := h.watchlistSvc.Remove(c.Request.Context(), userID, id); err != nil {
+		h.writeError(c, "remove watchlist item", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *WatchlistHandler) Move(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.MoveWatchlistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := currentUser(c).UserID
+	resp, err := h.watchlistSvc.Move(c.Request.Context(), userID, id, req)
+	if err != nil {
+		h.writeError(c, "move watchlist item", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *WatchlistHandler) MarkWatched(c *gin.Context) {
+	h.setWatched(c, true)
+}
+
+func (h *WatchlistHandler) UnmarkWatched(c *gin.Context) {
+	h.setWatched(c, false)
+}
+
+func (h *WatchlistHandler) setWatched(c *gin.Context, watched bool) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+
+	userID := currentUser(c).UserID
+	resp, err := h.watchlistSvc.SetWatched(c.Request.Context(), userID, id, watched)
+	if err != nil {
+		h.writeError(c, "set watched", err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *WatchlistHandler) writeError(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrMovieNotFound),
+		errors.Is(err, service.ErrUserNotFound),
+		errors.Is(err, service.ErrWatchlistItemNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrProfilePrivate), errors.Is(err, service.ErrWatchlistPrivate):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAlreadyInWatchlist):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidMove):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s error: %v", op, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,87 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/token"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	envDeletedCommentRetention   = "DELETED_COMMENT_RETENTION"
+	envReadNotificationRetention = "READ_NOTIFICATION_RETENTION"
+
+	defaultDeletedCommentRetention   = 30 * 24 * time.Hour
+	defaultReadNotificationRetention = 90 * 24 * time.Hour
+)
+
+// Maintenance returns the housekeeping jobs. Retention windows can be
+// overridden with Go durations, e.g. READ_NOTIFICATION_RETENTION=720h.
+func Maintenance() []Job {
+	commentRetention := durationFromEnv(envDeletedCommentRetention, defaultDeletedCommentRetention)
+	notificationRetention := durationFromEnv(envReadNotificationRetention, defaultReadNotificationRetention)
+
+	return []Job{
+		{
+			Name:     "purge_expired_sessions",
+			Interval: time.Hour,
+			Run: func(ctx context.Context, q *db.Queries) (int64, error) {
+				sessions, err := q.DeleteExpiredSessions(ctx)
+				if err != nil {
+					return 0, err
+				}
+				rotated, err := q.DeleteOldRotatedRefreshTokens(ctx, before(token.RefreshTokenDuration))
+				return sessions + rotated, err
+			},
+		},
+		{
+			Name:     "purge_expired_password_resets",
+			Interval: time.Hour,
+			Run: func(ctx context.Context, q *db.Queries) (int64, error) {
+				return q.DeleteExpiredPasswordResetTokens(ctx)
+			},
+		},
+		{
+			Name:     "purge_expired_oauth_exchange_codes",
+			Interval: time.Hour,
+			Run: func(ctx context.Context, q *db.Queries) (int64, error) {
+				return q.DeleteExpiredOAuthExchangeCodes(ctx)
+			},
+		},
+		{
+			Name:     "purge_deleted_comments",
+			Interval: 24 * time.Hour,
+			Run: func(ctx context.Context, q *db.Queries) (int64, error) {
+				return q.PurgeDeletedComments(ctx, before(commentRetention))
+			},
+		},
+		{
+			Name:     "prune_read_notifications",
+			Interval: 24 * time.Hour,
+			Run: func(ctx context.Context, q *db.Queries) (int64, error) {
+				return q.DeleteOldReadNotifications(ctx, before(notificationRetention))
+			},
+		},
+	}
+}
+
+func before(age time.Duration) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: time.Now().Add(-age), Valid: true}
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Printf("ignoring invalid %s=%q, using %s", key, v, fallback)
+		return fallback
+	}
+	return d
+}
@@ -0,0 +1,125 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Delay before the first run, so jobs stay out of the way of startup
+const initialDelay = time.Minute
+
+// Job is a periodic task. Run gets queries bound to the job's transaction and
+// returns how many rows it touched, for the log.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context, q *db.Queries) (int64, error)
+}
+
+// Scheduler runs jobs in the background on every replica. Each run takes a
+// transaction-scoped advisory lock keyed by the job name and then claims the
+// job's row in job_runs, which is only granted once the interval has passed
+// since the last run on any replica. So a job runs once per interval however
+// many replicas there are.
+type Scheduler struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+	jobs    []Job
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler(pool *pgxpool.Pool, jobs ...Job) *Scheduler {
+	return &Scheduler{pool: pool, queries: db.New(pool), jobs: jobs}
+}
+
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+// Stop cancels running jobs and waits for them to return
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	timer := time.NewTimer(initialDelay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		s.run(ctx, job)
+		timer.Reset(job.Interval)
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		log.Printf("job %s: %v", job.Name, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.queries.WithTx(tx)
+
+	locked, err := qtx.TryJobLock(ctx, job.Name)
+	if err != nil {
+		log.Printf("job %s: lock: %v", job.Name, err)
+		return
+	}
+	if !locked {
+		// Another replica is running it
+		return
+	}
+
+	claimed, err := qtx.ClaimJobRun(ctx, db.ClaimJobRunParams{
+		Job:             job.Name,
+		IntervalSeconds: int32(job.Interval / time.Second),
+	})
+	if err != nil {
+		log.Printf("job %s: claim: %v", job.Name, err)
+		return
+	}
+	if claimed == 0 {
+		// Another replica ran it within the interval
+		return
+	}
+
+	start := time.Now()
+	n, err := job.Run(ctx, qtx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("job %s: %v", job.Name, err)
+		}
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("job %s: commit: %v", job.Name, err)
+		return
+	}
+	if n > 0 {
+		log.Printf("job %s: %d rows in %s", job.Name, n, time.Since(start).Round(time.Millisecond))
+	}
+}
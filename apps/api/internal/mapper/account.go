@@ -0,0 +1,21 @@
+package mapper
+
+import (
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+)
+
+func ToAccountResponse(a db.Account) dto.AccountResponse {
+	return dto.AccountResponse{
+		Provider: a.Provider,
+		LinkedAt: a.CreatedAt.Time,
+	}
+}
+
+func ToAccountResponses(accounts []db.Account) []dto.AccountResponse {
+	out := make([]dto.AccountResponse, 0, len(accounts))
+	for _, a := range accounts {
+		out = append(out, ToAccountResponse(a))
+	}
+	return out
+}
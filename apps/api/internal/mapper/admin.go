@@ -0,0 +1,40 @@
+package mapper
+
+import (
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+)
+
+func ToAdminUserResponse(u db.User) dto.AdminUserResponse {
+	return dto.AdminUserResponse{
+		ID:          u.ID,
+		Email:       u.Email,
+		Username:    u.Username,
+		DisplayName: u.DisplayName.String,
+		Role:        string(u.Role),
+		Status:      string(u.Status),
+		IsVerified:  u.IsVerified,
+		CreatedAt:   u.CreatedAt.Time,
+	}
+}
+
+func ToStatusLogResponses(rows []db.ListUserStatusLogsRow) []dto.StatusLogResponse {
+	out := make([]dto.StatusLogResponse, 0, len(rows))
+	for _, r := range rows {
+		entry := dto.StatusLogResponse{
+			ID:        r.ID,
+			NewStatus: string(r.NewStatus),
+			Reason:    textPtr(r.Reason),
+			CreatedAt: r.CreatedAt.Time,
+		}
+		if r.OldStatus.Valid {
+			old := string(r.OldStatus.UserStatus)
+			entry.OldStatus = &old
+		}
+		if r.ChangedBy.Valid && r.ChangedByUsername.Valid {
+			entry.ChangedBy = &dto.AuthorResponse{ID: r.ChangedBy.Int32, Username: r.ChangedByUsername.String}
+		}
+		out = append(out, entry)
+	}
+	return out
+}
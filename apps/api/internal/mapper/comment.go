@@ -0,0 +1,83 @@
+package mapper
+
+import (
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+)
+
+// BuildCommentTree assembles flat ListCommentTree rows into nested threads.
+// Roots are returned in rootIDs order and replies oldest first. Deleted
+// comments keep their place as "[deleted]" while they still have replies;
+// deleted leaves are dropped. maxDepth is the depth the rows were fetched to
+// and reactions is keyed by comment id.
+func BuildCommentTree(rows []db.ListCommentTreeRow, rootIDs []int32, maxDepth int32, reactions map[int32]dto.ReactionSummary) []dto.CommentResponse {
+	type node struct {
+		resp     dto.CommentResponse
+		children []*node
+	}
+
+	nodes := make(map[int32]*node, len(rows))
+	// Rows arrive ordered by depth, so a parent is always seen before its replies
+	for _, r := range rows {
+		n := &node{resp: toCommentResponse(r, maxDepth)}
+		if summary, ok := reactions[r.ID]; ok {
+			n.resp.Reactions = summary
+		} else {
+			n.resp.Reactions = dto.NewReactionSummary()
+		}
+		nodes[r.ID] = n
+		if r.Depth > 0 && r.ParentID.Valid {
+			if parent, ok := nodes[r.ParentID.Int32]; ok {
+				parent.children = append(parent.children, n)
+			}
+		}
+	}
+
+	var build func(n *node) (dto.CommentResponse, bool)
+	build = func(n *node) (dto.CommentResponse, bool) {
+		resp := n.resp
+		resp.Replies = make([]dto.CommentResponse, 0, len(n.children))
+		for _, child := range n.children {
+			if c, keep := build(child); keep {
+				resp.Replies = append(resp.Replies, c)
+			}
+		}
+		keep := !resp.IsDeleted || len(resp.Replies) > 0 || resp.HasMoreReplies
+		return resp, keep
+	}
+
+	out := make([]dto.CommentResponse, 0, len(rootIDs))
+	for _, id := range rootIDs {
+		if n, ok := nodes[id]; ok {
+			if c, keep := build(n); keep {
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
+
+func toCommentResponse(r db.ListCommentTreeRow, maxDepth int32) dto.CommentResponse {
+	resp := dto.CommentResponse{
+		ID:             r.ID,
+		ParentID:       int4Ptr(r.ParentID),
+		Content:        r.Content,
+		LikeCount:      r.LikeCount.Int32,
+		ReplyCount:     r.ReplyCount,
+		HasMoreReplies: r.Depth >= maxDepth && r.ReplyCount > 0,
+		CreatedAt:      r.CreatedAt.Time,
+		UpdatedAt:      r.UpdatedAt.Time,
+		Author: &dto.AuthorResponse{
+			ID:          r.UserID,
+			Username:    r.Username,
+			DisplayName: r.DisplayName.String,
+			AvatarURL:   textPtr(r.AvatarUrl),
+		},
+	}
+	if r.DeletedAt.Valid {
+		resp.IsDeleted = true
+		resp.Content = dto.DeletedCommentContent
+		resp.Author = nil
+	}
+	return resp
+}
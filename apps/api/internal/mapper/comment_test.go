@@ -0,0 +1,182 @@
+package mapper
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func commentRow(id, parentID, depth int32, deleted bool, replyCount int64) db.ListCommentTreeRow {
+	r := db.ListCommentTreeRow{
+		ID:         id,
+		UserID:     100 + id,
+		Content:    "comment " + strconv.Itoa(int(id)),
+		Depth:      depth,
+		ReplyCount: replyCount,
+		Username:   "user" + strconv.Itoa(int(id)),
+		CreatedAt:  pgtype.Timestamptz{Time: time.Unix(int64(id), 0), Valid: true},
+	}
+	if parentID != 0 {
+		r.ParentID = pgtype.Int4{Int32: parentID, Valid: true}
+	}
+	if deleted {
+		r.DeletedAt = pgtype.Timestamptz{Time: time.Unix(1000, 0), Valid: true}
+	}
+	return r
+}
+
+// shape renders a tree as "1(2(3) 4)", marking deleted comments with x and
+// truncated ones with +
+func shape(comments []dto.CommentResponse) string {
+	parts := make([]string, len(comments))
+	for i, c := range comments {
+		s := strconv.Itoa(int(c.ID))
+		if c.IsDeleted {
+			s += "x"
+		}
+		if c.HasMoreReplies {
+			s += "+"
+		}
+		if len(c.Replies) > 0 {
+			s += "(" + shape(c.Replies) + ")"
+		}
+		parts[i] = s
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestBuildCommentTree(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     []db.ListCommentTreeRow
+		rootIDs  []int32
+		maxDepth int32
+		want     string
+	}{
+		{
+			name:     "empty",
+			maxDepth: 3,
+			want:     "",
+		},
+		{
+			name: "roots in rootIDs order, replies in row order",
+			rows: []db.ListCommentTreeRow{
+				commentRow(1, 0, 0, false, 2),
+				commentRow(2, 0, 0, false, 0),
+				commentRow(3, 1, 1, false, 1),
+				commentRow(4, 1, 1, false, 0),
+				commentRow(5, 3, 2, false, 0),
+			},
+			rootIDs:  []int32{2, 1},
+			maxDepth: 3,
+			want:     "2 1(3(5) 4)",
+		},
+		{
+			name: "deleted leaves are dropped",
+			rows: []db.ListCommentTreeRow{
+				commentRow(1, 0, 0, false, 2),
+				commentRow(2, 0, 0, true, 0),
+				commentRow(3, 1, 1, true, 0),
+				commentRow(4, 1, 1, false, 0),
+			},
+			rootIDs:  []int32{1, 2},
+			maxDepth: 3,
+			want:     "1(4)",
+		},
+		{
+			name: "deleted comments with replies are kept",
+			rows: []db.ListCommentTreeRow{
+				commentRow(1, 0, 0, true, 1),
+				commentRow(2, 1, 1, true, 1),
+				commentRow(3, 2, 2, false, 0),
+			},
+			rootIDs:  []int32{1},
+			maxDepth: 3,
+			want:     "1x(2x(3))",
+		},
+		{
+			name: "a deleted subtree with no live replies is dropped whole",
+			rows: []db.ListCommentTreeRow{
+				commentRow(1, 0, 0, true, 1),
+				commentRow(2, 1, 1, true, 0),
+			},
+			rootIDs:  []int32{1},
+			maxDepth: 3,
+			want:     "",
+		},
+		{
+			name: "comments at max depth report more replies",
+			rows: []db.ListCommentTreeRow{
+				commentRow(1, 0, 0, false, 1),
+				commentRow(2, 1, 1, false, 1),
+				commentRow(3, 1, 1, true, 4),
+			},
+			rootIDs:  []int32{1},
+			maxDepth: 1,
+			want:     "1(2+ 3x+)",
+		},
+		{
+			name: "rows without a fetched parent and unknown roots are skipped",
+			rows: []db.ListCommentTreeRow{
+				commentRow(1, 0, 0, false, 0),
+				commentRow(5, 4, 1, false, 0),
+			},
+			rootIDs:  []int32{9, 1},
+			maxDepth: 3,
+			want:     "1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildCommentTree(tt.rows, tt.rootIDs, tt.maxDepth, nil)
+			if got == nil {
+				t.Fatal("got nil, want an empty slice")
+			}
+			if s := shape(got); s != tt.want {
+				t.Errorf("tree = %q, want %q", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildCommentTreeFields(t *testing.T) {
+	mine := "LOVE"
+	summary := dto.NewReactionSummary()
+	summary.Counts["LOVE"] = 2
+	summary.Total = 2
+	summary.Mine = &mine
+
+	rows := []db.ListCommentTreeRow{
+		commentRow(1, 0, 0, true, 1),
+		commentRow(2, 1, 1, false, 0),
+	}
+	got := BuildCommentTree(rows, []int32{1}, 3, map[int32]dto.ReactionSummary{2: summary})
+
+	root := got[0]
+	if root.Content != dto.DeletedCommentContent || root.Author != nil {
+		t.Errorf("deleted root content = %q, author = %v; want redacted", root.Content, root.Author)
+	}
+	if root.Reactions.Counts == nil || root.Reactions.Total != 0 {
+		t.Errorf("root reactions = %+v, want an empty summary", root.Reactions)
+	}
+
+	reply := root.Replies[0]
+	if reply.Content != "comment 2" || reply.Author == nil || reply.Author.Username != "user2" {
+		t.Errorf("reply = %+v, want its content and author", reply)
+	}
+	if reply.ParentID == nil || *reply.ParentID != 1 {
+		t.Errorf("reply parent = %v, want 1", reply.ParentID)
+	}
+	if reply.Reactions.Total != 2 || reply.Reactions.Mine == nil || *reply.Reactions.Mine != mine {
+		t.Errorf("reply reactions = %+v, want the given summary", reply.Reactions)
+	}
+	if reply.Replies == nil {
+		t.Error("reply replies = nil, want an empty slice")
+	}
+}
@@ -0,0 +1,55 @@
+package mapper
+
+import (
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+)
+
+func ToFeedActor(r db.ListFeedActivitiesRow) dto.AuthorResponse {
+	return dto.AuthorResponse{
+		ID:          r.UserID,
+		Username:    r.Username,
+		DisplayName: r.DisplayName.String,
+		AvatarURL:   textPtr(r.AvatarUrl),
+	}
+}
+
+// ToFeedItemResponse hydrates an activity from its joined entity columns. It
+// reports false when the entity no longer exists and the activity should be skipped.
+func ToFeedItemResponse(r db.ListFeedActivitiesRow) (dto.FeedItemResponse, bool) {
+	item := dto.FeedItemResponse{
+		ActivityID: r.ID,
+		Metadata:   r.Metadata,
+		CreatedAt:  r.CreatedAt.Time,
+	}
+
+	switch r.EntityType {
+	case db.EntityTypeMOVIE, db.EntityTypeREVIEW:
+		if !r.MovieID.Valid {
+			return item, false
+		}
+		item.Movie = &dto.MovieRefResponse{
+			ID:          r.MovieID.Int32,
+			Title:       r.MovieTitle.String,
+			Slug:        r.MovieSlug.String,
+			PosterURL:   textPtr(r.MoviePosterUrl),
+			ReleaseDate: datePtr(r.MovieReleaseDate),
+		}
+		if r.EntityType == db.EntityTypeREVIEW {
+			item.Review = &dto.FeedReviewRef{ID: r.ReviewID.Int32, Title: textPtr(r.ReviewTitle)}
+		}
+	case db.EntityTypeUSER:
+		if !r.TargetUsername.Valid {
+			return item, false
+		}
+		item.User = &dto.AuthorResponse{
+			ID:          r.EntityID,
+			Username:    r.TargetUsername.String,
+			DisplayName: r.TargetDisplayName.String,
+			AvatarURL:   textPtr(r.TargetAvatarUrl),
+		}
+	default:
+		return item, false
+	}
+	return item, true
+}
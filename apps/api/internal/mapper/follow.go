@@ -0,0 +1,22 @@
+package mapper
+
+import (
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+)
+
+// ToFollowUserResponses converts a page of follow rows. ListFollowingRow and
+// ListMutualFollowsRow share the ListFollowersRow shape and convert directly.
+func ToFollowUserResponses(rows []db.ListFollowersRow) []dto.FollowUserResponse {
+	out := make([]dto.FollowUserResponse, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, dto.FollowUserResponse{
+			ID:          r.ID,
+			Username:    r.Username,
+			DisplayName: r.DisplayName.String,
+			AvatarURL:   textPtr(r.AvatarUrl),
+			FollowedAt:  r.FollowedAt.Time,
+		})
+	}
+	return out
+}
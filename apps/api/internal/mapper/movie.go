@@ -0,0 +1,84 @@
+package mapper
+
+import (
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+)
+
+// ToMovieSummaryResponse converts a db.Movie to the compact list shape
+func ToMovieSummaryResponse(m db.Movie) dto.MovieSummaryResponse {
+	return dto.MovieSummaryResponse{
+		ID:              m.ID,
+		Title:           m.Title,
+		Slug:            m.Slug,
+		PosterURL:       textPtr(m.PosterUrl),
+		ReleaseDate:     datePtr(m.ReleaseDate),
+		Runtime:         int4Ptr(m.Runtime),
+		UserAvgRating:   m.UserAvgRating.Float32,
+		UserRatingCount: m.UserRatingCount.Int32,
+		ImdbRating:      numericPtr(m.ImdbRating),
+	}
+}
+
+// ToMovieSummaryResponses converts a slice of db.Movie
+func ToMovieSummaryResponses(movies []db.Movie) []dto.MovieSummaryResponse {
+	out := make([]dto.MovieSummaryResponse, 0, len(movies))
+	for _, m := range movies {
+		out = append(out, ToMovieSummaryResponse(m))
+	}
+	return out
+}
+
+// ToMovieDetailResponse assembles the movie page from the movie row and its relations
+func ToMovieDetailResponse(m db.Movie, genres []db.Genre, directors, cast []db.ListMovieCreditsRow) dto.MovieDetailResponse {
+	return dto.MovieDetailResponse{
+		MovieSummaryResponse: ToMovieSummaryResponse(m),
+		Overview:             textPtr(m.Overview),
+		BackdropURL:          textPtr(m.BackdropUrl),
+		TrailerURL:           textPtr(m.TrailerUrl),
+		ContentRating:        textPtr(m.ContentRating),
+		OriginalLanguage:     textPtr(m.OriginalLanguage),
+		Country:              textPtr(m.Country),
+		ImdbID:               textPtr(m.ImdbID),
+		RottenTomatoes:       int4Ptr(m.RottenTomatoes),
+		MetacriticScore:      int4Ptr(m.MetacriticScore),
+		LetterboxdRating:     numericPtr(m.LetterboxdRating),
+		Genres:               ToGenreResponses(genres),
+		Directors:            ToCreditResponses(directors),
+		Cast:                 ToCreditResponses(cast),
+	}
+}
+
+// ToGenreResponses converts a slice of db.Genre
+func ToGenreResponses(genres []db.Genre) []dto.GenreResponse {
+	out := make([]dto.GenreResponse, 0, len(genres))
+	for _, g := range genres {
+		out = append(out, dto.GenreResponse{ID: g.ID, Name: g.Name, Slug: g.Slug})
+	}
+	return out
+}
+
+// ToCreditResponses converts credit rows joined with their persons
+func ToCreditResponses(rows []db.ListMovieCreditsRow) []dto.CreditResponse {
+	out := make([]dto.CreditResponse, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, dto.CreditResponse{
+			PersonID:  r.PersonID,
+			Name:      r.Name,
+			Slug:      r.Slug,
+			PhotoURL:  textPtr(r.PhotoUrl),
+			Role:      r.Role,
+			Character: textPtr(r.Character),
+		})
+	}
+	return out
+}
+
+// ToMovieStatsResponse extracts the community rating aggregate from a db.Movie
+func ToMovieStatsResponse(m db.Movie) dto.MovieStatsResponse {
+	return dto.MovieStatsResponse{
+		MovieID:         m.ID,
+		UserAvgRating:   m.UserAvgRating.Float32,
+		UserRatingCount: m.UserRatingCount.Int32,
+	}
+}
@@ -0,0 +1,34 @@
+package mapper
+
+import (
+	"encoding/json"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+)
+
+func ToNotificationResponse(n db.Notification) dto.NotificationResponse {
+	resp := dto.NotificationResponse{
+		ID:        n.ID,
+		Type:      string(n.Type),
+		Title:     n.Title,
+		Content:   textPtr(n.Content),
+		IsRead:    n.IsRead,
+		CreatedAt: n.CreatedAt.Time,
+	}
+	if len(n.Metadata) > 0 {
+		var meta dto.NotificationMetadata
+		if err := json.Unmarshal(n.Metadata, &meta); err == nil {
+			resp.Metadata = &meta
+		}
+	}
+	return resp
+}
+
+func ToNotificationResponses(rows []db.Notification) []dto.NotificationResponse {
+	out := make([]dto.NotificationResponse, 0, len(rows))
+	for _, n := range rows {
+		out = append(out, ToNotificationResponse(n))
+	}
+	return out
+}
@@ -0,0 +1,36 @@
+package mapper
+
+import (
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+)
+
+// ToRatingResponse combines a rating with the movie row read back after the stats trigger ran
+func ToRatingResponse(r db.Rating, m db.Movie) dto.RatingResponse {
+	return dto.RatingResponse{
+		Score:      r.Score,
+		CreatedAt:  r.CreatedAt.Time,
+		UpdatedAt:  r.UpdatedAt.Time,
+		MovieStats: ToMovieStatsResponse(m),
+	}
+}
+
+// ToUserRatingResponses converts a page of a user's ratings
+func ToUserRatingResponses(rows []db.ListUserRatingsRow) []dto.UserRatingResponse {
+	out := make([]dto.UserRatingResponse, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, dto.UserRatingResponse{
+			Score:     r.Score,
+			CreatedAt: r.CreatedAt.Time,
+			UpdatedAt: r.UpdatedAt.Time,
+			Movie: dto.MovieRefResponse{
+				ID:          r.MovieID,
+				Title:       r.Title,
+				Slug:        r.Slug,
+				PosterURL:   textPtr(r.PosterUrl),
+				ReleaseDate: datePtr(r.ReleaseDate),
+			},
+		})
+	}
+	return out
+}
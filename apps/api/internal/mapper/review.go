@@ -0,0 +1,32 @@
+package mapper
+
+import (
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+)
+
+// ToReviewResponse converts a review view row. ListMovieReviewsRow and
+// ListUserReviewsRow share its shape and can be passed via a type conversion.
+func ToReviewResponse(r db.GetReviewViewRow) dto.ReviewResponse {
+	return dto.ReviewResponse{
+		ID:           r.ID,
+		Title:        textPtr(r.Title),
+		Content:      r.Content,
+		LikeCount:    r.LikeCount.Int32,
+		AuthorRating: int4Ptr(r.AuthorRating),
+		Author: dto.AuthorResponse{
+			ID:          r.UserID,
+			Username:    r.Username,
+			DisplayName: r.AuthorDisplayName.String,
+			AvatarURL:   textPtr(r.AuthorAvatarUrl),
+		},
+		Movie: dto.MovieRefResponse{
+			ID:        r.MovieID,
+			Title:     r.MovieTitle,
+			Slug:      r.MovieSlug,
+			PosterURL: textPtr(r.MoviePosterUrl),
+		},
+		CreatedAt: r.CreatedAt.Time,
+		UpdatedAt: r.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,35 @@
+package mapper
+
+import (
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+)
+
+// ToMovieSearchResult converts a movie search hit
+func ToMovieSearchResult(r db.SearchMoviesRow) dto.SearchResult {
+	res := dto.SearchResult{
+		Type:     dto.SearchTypeMovie,
+		ID:       r.ID,
+		Name:     r.Title,
+		Slug:     r.Slug,
+		ImageURL: textPtr(r.PosterUrl),
+		Score:    r.Score,
+	}
+	if r.ReleaseDate.Valid {
+		year := r.ReleaseDate.Time.Year()
+		res.Year = &year
+	}
+	return res
+}
+
+// ToPersonSearchResult converts a person search hit
+func ToPersonSearchResult(r db.SearchPersonsRow) dto.SearchResult {
+	return dto.SearchResult{
+		Type:     dto.SearchTypePerson,
+		ID:       r.ID,
+		Name:     r.Name,
+		Slug:     r.Slug,
+		ImageURL: textPtr(r.PhotoUrl),
+		Score:    r.Score,
+	}
+}
@@ -0,0 +1,44 @@
+package mapper
+
+import (
+	"encoding/json"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+)
+
+// ToSessionResponses flags the session matching currentID, if any
+func ToSessionResponses(sessions []db.Session, currentID string) []dto.SessionResponse {
+	out := make([]dto.SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		out = append(out, dto.SessionResponse{
+			ID:         s.ID,
+			UserAgent:  textPtr(s.UserAgent),
+			IPAddress:  textPtr(s.IpAddress),
+			CreatedAt:  s.CreatedAt.Time,
+			LastUsedAt: s.LastUsedAt.Time,
+			ExpiresAt:  s.ExpiresAt.Time,
+			Current:    currentID != "" && s.ID == currentID,
+		})
+	}
+	return out
+}
+
+func ToSecurityEventResponses(rows []db.SecurityEvent) []dto.SecurityEventResponse {
+	out := make([]dto.SecurityEventResponse, 0, len(rows))
+	for _, e := range rows {
+		resp := dto.SecurityEventResponse{
+			ID:        e.ID,
+			Type:      e.Type,
+			SessionID: textPtr(e.SessionID),
+			IPAddress: textPtr(e.IpAddress),
+			UserAgent: textPtr(e.UserAgent),
+			CreatedAt: e.CreatedAt.Time,
+		}
+		if len(e.Metadata) > 0 {
+			_ = json.Unmarshal(e.Metadata, &resp.Metadata)
+		}
+		out = append(out, resp)
+	}
+	return out
+}
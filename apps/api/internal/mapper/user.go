@@ -8,9 +8,55 @@ import (
 // ToUserResponse converts a db.User to dto.UserResponse
 func ToUserResponse(user db.User) dto.UserResponse {
 	return dto.UserResponse{
-		ID:          user.ID,
-		Email:       user.Email,
-		Username:    user.Username,
-		DisplayName: user.DisplayName.String,
+		ID:               user.ID,
+		Email:            user.Email,
+		Username:         user.Username,
+		DisplayName:      user.DisplayName.String,
+		AvatarURL:        textPtr(user.AvatarUrl),
+		Bio:              textPtr(user.Bio),
+		IsVerified:       user.IsVerified,
+		ProfilePrivate:   user.ProfilePrivate,
+		WatchlistPrivate: user.WatchlistPrivate,
 	}
 }
+
+// ToPublicProfileResponse converts a db.User to its public profile; stats are attached by the caller
+func ToPublicProfileResponse(user db.User, isFollowing bool) dto.PublicProfileResponse {
+	return dto.PublicProfileResponse{
+		ID:               user.ID,
+		Username:         user.Username,
+		DisplayName:      user.DisplayName.String,
+		AvatarURL:        textPtr(user.AvatarUrl),
+		Bio:              textPtr(user.Bio),
+		JoinedAt:         user.CreatedAt.Time,
+		ProfilePrivate:   user.ProfilePrivate,
+		WatchlistPrivate: user.WatchlistPrivate,
+		IsFollowing:      isFollowing,
+	}
+}
+
+// ToProfileStats builds the stats block with a histogram bucket for every
+// possible score, including empty ones
+func ToProfileStats(stats db.GetUserStatsRow, histogram []db.GetUserRatingHistogramRow, showWatchlist bool) dto.ProfileStats {
+	buckets := make([]dto.RatingBucket, 11)
+	for i := range buckets {
+		buckets[i].Score = int32(i)
+	}
+	for _, h := range histogram {
+		if h.Score >= 0 && int(h.Score) < len(buckets) {
+			buckets[h.Score].Count = h.Count
+		}
+	}
+
+	out := dto.ProfileStats{
+		RatingCount:     stats.RatingCount,
+		ReviewCount:     stats.ReviewCount,
+		FollowerCount:   stats.FollowerCount,
+		FollowingCount:  stats.FollowingCount,
+		RatingHistogram: buckets,
+	}
+	if showWatchlist {
+		out.WatchlistCount = &stats.WatchlistCount
+	}
+	return out
+}
@@ -0,0 +1,31 @@
+package mapper
+
+import (
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+)
+
+// ToWatchlistItemResponse converts a watchlist row; ListWatchlistRow has the same shape
+func ToWatchlistItemResponse(r db.GetWatchlistItemViewRow) dto.WatchlistItemResponse {
+	return dto.WatchlistItemResponse{
+		ID:        r.ID,
+		Notes:     textPtr(r.Notes),
+		WatchedAt: timePtr(r.WatchedAt),
+		AddedAt:   r.CreatedAt.Time,
+		Movie: dto.MovieRefResponse{
+			ID:          r.MovieID,
+			Title:       r.Title,
+			Slug:        r.Slug,
+			PosterURL:   textPtr(r.PosterUrl),
+			ReleaseDate: datePtr(r.ReleaseDate),
+		},
+	}
+}
+
+func ToWatchlistItemResponses(rows []db.ListWatchlistRow) []dto.WatchlistItemResponse {
+	out := make([]dto.WatchlistItemResponse, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, ToWatchlistItemResponse(db.GetWatchlistItemViewRow(r)))
+	}
+	return out
+}
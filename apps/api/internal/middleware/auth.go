@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+	"log"
 	"net/http"
 	"strings"
 
@@ -8,29 +10,103 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware(jwt *token.JWTManager) gin.HandlerFunc {
+// SessionChecker reports whether the session an access token was issued with
+// still grants access. Tokens are otherwise valid until they expire, so this
+// is what makes logout and bans take effect immediately.
+type SessionChecker interface {
+	SessionActive(ctx context.Context, userID int32, sessionID string) (bool, error)
+}
+
+// AuthMiddleware accepts a bearer token or, for browsers in cookie auth mode,
+// the access token cookie. The header wins when both are sent.
+func AuthMiddleware(jwt *token.JWTManager, sessions SessionChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
+		if authHeader == "" && !hasAccessCookie(c) {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
 			return
 		}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
+		if !authenticate(c, jwt, sessions, authHeader) {
 			return
 		}
 
-		claims, err := jwt.Verify(parts[1])
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware is for routes that serve everyone but behave
+// differently for signed-in users. It identifies the caller when a token is
+// sent but lets anonymous requests through. A token that is sent but invalid is still
+// rejected, so clients know to refresh instead of silently losing personalisation.
+func OptionalAuthMiddleware(jwt *token.JWTManager, sessions SessionChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if (authHeader != "" || hasAccessCookie(c)) && !authenticate(c, jwt, sessions, authHeader) {
 			return
 		}
 
-		c.Set("user_id", int32(claims["sub"].(float64)))
-		c.Set("user_role", claims["role"].(string))
-
 		c.Next()
 	}
 }
+
+// RequireRole must run after AuthMiddleware. It rejects callers whose token
+// role is not one of roles.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if p, ok := PrincipalFrom(c); ok && p.HasRole(roles...) {
+			c.Next()
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+	}
+}
+
+// authenticate verifies the bearer token, or the access cookie when there is
+// no header, checks its session has not been revoked and stores the caller in
+// the context. It aborts the request and returns false when the header, token,
+// CSRF token or session is invalid.
+func authenticate(c *gin.Context, jwt *token.JWTManager, sessions SessionChecker, authHeader string) bool {
+	var tokenStr string
+	if authHeader != "" {
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
+			return false
+		}
+		tokenStr = parts[1]
+	} else {
+		// Browsers attach cookies to cross-site requests too
+		if !ValidCSRF(c) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "missing or invalid csrf token"})
+			return false
+		}
+		tokenStr, _ = c.Cookie(AccessTokenCookie)
+	}
+
+	claims, err := jwt.Verify(tokenStr)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+		return false
+	}
+
+	p := newPrincipal(claims)
+	active, err := sessions.SessionActive(c.Request.Context(), p.UserID, p.SessionID)
+	if err != nil {
+		log.Printf("session check error: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return false
+	}
+	if !active {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "session has been revoked"})
+		return false
+	}
+
+	c.Set(principalKey, p)
+	return true
+}
+
+func hasAccessCookie(c *gin.Context) bool {
+	v, err := c.Cookie(AccessTokenCookie)
+	return err == nil && v != ""
+}
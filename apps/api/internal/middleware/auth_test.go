@@ -0,0 +1,295 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/token"
+	"github.com/MassoudJavadi/filmophilia/api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// stubSessions treats every session as active except the revoked ones
+type stubSessions struct {
+	revoked map[string]bool
+}
+
+func (s stubSessions) SessionActive(_ context.Context, _ int32, sessionID string) (bool, error) {
+	return !s.revoked[sessionID], nil
+}
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestValidCSRF(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		cookie string
+		header string
+		want   bool
+	}{
+		{"GET needs no token", http.MethodGet, "", "", true},
+		{"HEAD needs no token", http.MethodHead, "", "", true},
+		{"OPTIONS needs no token", http.MethodOptions, "", "", true},
+		{"matching token", http.MethodPost, "csrf-1", "csrf-1", true},
+		{"matching token on DELETE", http.MethodDelete, "csrf-1", "csrf-1", true},
+		{"no cookie", http.MethodPost, "", "csrf-1", false},
+		{"no header", http.MethodPost, "csrf-1", "", false},
+		{"neither", http.MethodPatch, "", "", false},
+		{"mismatch", http.MethodPut, "csrf-1", "csrf-2", false},
+		{"prefix of the cookie", http.MethodPost, "csrf-1", "csrf-", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(tt.method, "/", nil)
+			if tt.cookie != "" {
+				c.Request.AddCookie(&http.Cookie{Name: CSRFCookie, Value: tt.cookie})
+			}
+			if tt.header != "" {
+				c.Request.Header.Set(CSRFHeader, tt.header)
+			}
+
+			if got := ValidCSRF(c); got != tt.want {
+				t.Errorf("ValidCSRF = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareCookieFallback(t *testing.T) {
+	jwt := token.NewJWTManager("test-secret")
+	sessions := stubSessions{revoked: map[string]bool{"revoked": true}}
+
+	valid, err := jwt.Generate(7, "USER", "session-1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked, err := jwt.Generate(7, "USER", "revoked", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		header string
+		cookie string
+		csrf   bool
+		want   int
+	}{
+		{"nothing", http.MethodGet, "", "", false, http.StatusUnauthorized},
+		{"bearer header", http.MethodPost, "Bearer " + valid, "", false, http.StatusOK},
+		{"bearer header needs no csrf token", http.MethodPost, "Bearer " + valid, "ignored", false, http.StatusOK},
+		{"cookie on a safe method", http.MethodGet, "", valid, false, http.StatusOK},
+		{"cookie with csrf token", http.MethodPost, "", valid, true, http.StatusOK},
+		{"cookie without csrf token", http.MethodPost, "", valid, false, http.StatusForbidden},
+		{"header wins over cookie", http.MethodGet, "Bearer garbage", valid, false, http.StatusUnauthorized},
+		{"malformed header", http.MethodGet, "Token " + valid, "", false, http.StatusUnauthorized},
+		{"invalid cookie", http.MethodGet, "", "garbage", false, http.StatusUnauthorized},
+		{"revoked session via cookie", http.MethodGet, "", revoked, false, http.StatusUnauthorized},
+		{"revoked session via header", http.MethodGet, "Bearer " + revoked, "", false, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Handle(tt.method, "/", AuthMiddleware(jwt, sessions), func(c *gin.Context) {
+				p, ok := PrincipalFrom(c)
+				if !ok || p.UserID != 7 || p.SessionID == "" {
+					t.Errorf("principal = %+v, %v; want user 7", p, ok)
+				}
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: tt.cookie})
+			}
+			if tt.csrf {
+				req.AddCookie(&http.Cookie{Name: CSRFCookie, Value: "csrf-1"})
+				req.Header.Set(CSRFHeader, "csrf-1")
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d (%s)", w.Code, tt.want, w.Body)
+			}
+		})
+	}
+}
+
+func TestOptionalAuthMiddleware(t *testing.T) {
+	jwt := token.NewJWTManager("test-secret")
+	sessions := stubSessions{revoked: map[string]bool{"revoked": true}}
+
+	valid, err := jwt.Generate(7, "USER", "session-1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked, err := jwt.Generate(7, "USER", "revoked", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		header     string
+		cookie     string
+		want       int
+		wantViewer int32
+	}{
+		{"anonymous", "", "", http.StatusOK, 0},
+		{"bearer header", "Bearer " + valid, "", http.StatusOK, 7},
+		{"access cookie", "", valid, http.StatusOK, 7},
+		// A token that was sent must be good, or clients would silently lose personalisation
+		{"invalid token", "Bearer garbage", "", http.StatusUnauthorized, 0},
+		{"revoked session", "Bearer " + revoked, "", http.StatusUnauthorized, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var viewer int32
+			r := gin.New()
+			r.GET("/", OptionalAuthMiddleware(jwt, sessions), func(c *gin.Context) {
+				if p, ok := PrincipalFrom(c); ok {
+					viewer = p.UserID
+				}
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: tt.cookie})
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want || viewer != tt.wantViewer {
+				t.Errorf("status = %d, viewer = %d; want %d, %d", w.Code, viewer, tt.want, tt.wantViewer)
+			}
+		})
+	}
+}
+
+// benchDB stands in for Postgres behind SessionValidator: every session it is
+// asked about belongs to benchUserID, who is active
+type benchDB struct{}
+
+const benchUserID = 42
+
+func (benchDB) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, nil
+}
+
+func (benchDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return nil, pgx.ErrNoRows
+}
+
+func (benchDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	return activeSessionRow{}
+}
+
+type activeSessionRow struct{}
+
+func (activeSessionRow) Scan(dest ...any) error {
+	*dest[0].(*int32) = benchUserID
+	*dest[1].(*db.UserStatus) = db.UserStatusACTIVE
+	return nil
+}
+
+// BenchmarkAuthMiddleware measures what the session revocation check adds to
+// an authenticated request:
+//
+//   - signature only: no session check, how tokens were accepted before
+//   - cached session check: SessionValidator answering from its cache, as it
+//     does for most requests
+//   - cache miss: a session seen for the first time, which every session is
+//     again every sessionCacheTTL. benchDB answers in process, so this is the
+//     validator's own cost; with DATABASE_URL set, "cache miss, postgres"
+//     adds the query round trip (its sessions don't exist, so requests get 401)
+func BenchmarkAuthMiddleware(b *testing.B) {
+	jwt := token.NewJWTManager("bench-secret")
+
+	// tokens returns n access tokens, each for a session of its own
+	tokens := func(b *testing.B, n int) []string {
+		b.Helper()
+		out := make([]string, n)
+		for i := range out {
+			t, err := jwt.Generate(benchUserID, string(db.RoleUSER), fmt.Sprintf("bench-session-%d", i), token.AccessTokenDuration)
+			if err != nil {
+				b.Fatal(err)
+			}
+			out[i] = t
+		}
+		return out
+	}
+	router := func(sessions SessionChecker) http.Handler {
+		r := gin.New()
+		r.GET("/", AuthMiddleware(jwt, sessions), func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+		return r
+	}
+	// run serves one request per token, in the order given
+	run := func(b *testing.B, h http.Handler, tokens []string, want int) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := range b.N {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tokens[i%len(tokens)])
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != want {
+				b.Fatalf("status = %d, want %d", w.Code, want)
+			}
+		}
+	}
+
+	b.Run("signature only", func(b *testing.B) {
+		run(b, router(stubSessions{}), tokens(b, 1), http.StatusNoContent)
+	})
+
+	b.Run("cached session check", func(b *testing.B) {
+		validator := service.NewSessionValidator(nil, db.New(benchDB{}))
+		run(b, router(validator), tokens(b, 1), http.StatusNoContent)
+	})
+
+	b.Run("cache miss", func(b *testing.B) {
+		validator := service.NewSessionValidator(nil, db.New(benchDB{}))
+		run(b, router(validator), tokens(b, b.N), http.StatusNoContent)
+	})
+
+	b.Run("cache miss, postgres", func(b *testing.B) {
+		url := os.Getenv("DATABASE_URL")
+		if url == "" {
+			b.Skip("DATABASE_URL is not set")
+		}
+		pool, err := pgxpool.New(context.Background(), url)
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer pool.Close()
+		validator := service.NewSessionValidator(pool, db.New(pool))
+		run(b, router(validator), tokens(b, b.N), http.StatusUnauthorized)
+	})
+}
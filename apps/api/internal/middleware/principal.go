@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"slices"
+	"time"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/token"
+	"github.com/gin-gonic/gin"
+)
+
+// Context key the auth middlewares store the caller under
+const principalKey = "principal"
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	UserID int32
+	Role   string
+	// Session the access token was issued with
+	SessionID string
+	// The access token's jti
+	TokenID   string
+	ExpiresAt time.Time
+}
+
+func newPrincipal(claims *token.Claims) Principal {
+	p := Principal{
+		UserID:    claims.UserID(),
+		Role:      claims.Role,
+		SessionID: claims.SessionID,
+		TokenID:   claims.ID,
+	}
+	if claims.ExpiresAt != nil {
+		p.ExpiresAt = claims.ExpiresAt.Time
+	}
+	return p
+}
+
+func (p Principal) HasRole(roles ...string) bool {
+	return slices.Contains(roles, p.Role)
+}
+
+// PrincipalFrom returns the caller, or false when the request is anonymous
+func PrincipalFrom(c *gin.Context) (Principal, bool) {
+	v, ok := c.Get(principalKey)
+	if !ok {
+		return Principal{}, false
+	}
+	p, ok := v.(Principal)
+	return p, ok
+}
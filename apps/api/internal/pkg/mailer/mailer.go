@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+const (
+	envMailDriver   = "MAIL_DRIVER"
+	envMailFrom     = "MAIL_FROM"
+	envMailDir      = "MAIL_DIR"
+	envSMTPHost     = "SMTP_HOST"
+	envSMTPPort     = "SMTP_PORT"
+	envSMTPUsername = "SMTP_USERNAME"
+	envSMTPPassword = "SMTP_PASSWORD"
+
+	defaultFrom     = "Filmophilia <no-reply@filmophilia.local>"
+	defaultSMTPPort = "587"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers transactional email
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewMailer picks an implementation from MAIL_DRIVER: "smtp" sends through
+// SMTP_HOST, "file" writes each message to MAIL_DIR, and anything else
+// (the default) prints messages to the log for local development.
+func NewMailer() Mailer {
+	from := os.Getenv(envMailFrom)
+	if from == "" {
+		from = defaultFrom
+	}
+
+	switch strings.ToLower(os.Getenv(envMailDriver)) {
+	case "smtp":
+		port := os.Getenv(envSMTPPort)
+		if port == "" {
+			port = defaultSMTPPort
+		}
+		return NewSMTPMailer(os.Getenv(envSMTPHost), port, os.Getenv(envSMTPUsername), os.Getenv(envSMTPPassword), from)
+	case "file":
+		return NewFileMailer(os.Getenv(envMailDir), from)
+	default:
+		return NewLogMailer(from)
+	}
+}
@@ -0,0 +1,69 @@
+package oauth
+
+import (
+	"context"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	discordAuthURL  = "https://discord.com/oauth2/authorize"
+	discordTokenURL = "https://discord.com/api/oauth2/token"
+	discordUserURL  = "https://discord.com/api/users/@me"
+	discordCDN      = "https://cdn.discordapp.com"
+)
+
+type discordUser struct {
+	ID         string `json:"id"`
+	Username   string `json:"username"`
+	GlobalName string `json:"global_name"`
+	Email      string `json:"email"`
+	Verified   bool   `json:"verified"`
+	Avatar     string `json:"avatar"`
+}
+
+type discordProvider struct {
+	oauth2Provider
+}
+
+func NewDiscordProvider(c Credentials) Provider {
+	return &discordProvider{oauth2Provider{
+		name: "discord",
+		config: &oauth2.Config{
+			ClientID:     c.ClientID,
+			ClientSecret: c.ClientSecret,
+			RedirectURL:  c.RedirectURL,
+			Scopes:       []string{"identify", "email"},
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  discordAuthURL,
+				TokenURL: discordTokenURL,
+			},
+		},
+	}}
+}
+
+func (p *discordProvider) Identify(ctx context.Context, code string, flow Flow) (*UserInfo, error) {
+	token, err := p.exchange(ctx, code, flow)
+	if err != nil {
+		return nil, err
+	}
+
+	var dUser discordUser
+	if err := getJSON(ctx, discordUserURL, token.AccessToken, &dUser); err != nil {
+		return nil, err
+	}
+
+	info := &UserInfo{
+		Subject:       dUser.ID,
+		Email:         dUser.Email,
+		EmailVerified: dUser.Verified,
+		Name:          dUser.GlobalName,
+	}
+	if info.Name == "" {
+		info.Name = dUser.Username
+	}
+	if dUser.Avatar != "" {
+		info.Picture = discordCDN + "/avatars/" + dUser.ID + "/" + dUser.Avatar + ".png"
+	}
+	return info, nil
+}
@@ -0,0 +1,80 @@
+package oauth
+
+import (
+	"context"
+	"strconv"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+)
+
+const (
+	githubUserURL   = "https://api.github.com/user"
+	githubEmailsURL = "https://api.github.com/user/emails"
+)
+
+type githubUser struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+type githubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+type githubProvider struct {
+	oauth2Provider
+}
+
+func NewGitHubProvider(c Credentials) Provider {
+	return &githubProvider{oauth2Provider{
+		name: "github",
+		config: &oauth2.Config{
+			ClientID:     c.ClientID,
+			ClientSecret: c.ClientSecret,
+			RedirectURL:  c.RedirectURL,
+			Scopes:       []string{"read:user", "user:email"},
+			Endpoint:     github.Endpoint,
+		},
+	}}
+}
+
+// Identify reads the profile and the primary address from the emails API,
+// since the profile only shows an email the user chose to make public and
+// does not say whether it is verified
+func (p *githubProvider) Identify(ctx context.Context, code string, flow Flow) (*UserInfo, error) {
+	token, err := p.exchange(ctx, code, flow)
+	if err != nil {
+		return nil, err
+	}
+
+	var ghUser githubUser
+	if err := getJSON(ctx, githubUserURL, token.AccessToken, &ghUser); err != nil {
+		return nil, err
+	}
+	var emails []githubEmail
+	if err := getJSON(ctx, githubEmailsURL, token.AccessToken, &emails); err != nil {
+		return nil, err
+	}
+
+	info := &UserInfo{
+		Subject: strconv.FormatInt(ghUser.ID, 10),
+		Name:    ghUser.Name,
+		Picture: ghUser.AvatarURL,
+	}
+	if info.Name == "" {
+		info.Name = ghUser.Login
+	}
+	for _, e := range emails {
+		if e.Primary {
+			info.Email = e.Email
+			info.EmailVerified = e.Verified
+			break
+		}
+	}
+	return info, nil
+}
@@ -2,29 +2,19 @@ package oauth
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/base64"
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"os"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 )
 
 const (
-	envGoogleClientID     = "GOOGLE_CLIENT_ID"
-	envGoogleClientSecret = "GOOGLE_CLIENT_SECRET"
-	envGoogleRedirectURL  = "GOOGLE_REDIRECT_URL"
-
 	scopeEmail   = "https://www.googleapis.com/auth/userinfo.email"
 	scopeProfile = "https://www.googleapis.com/auth/userinfo.profile"
 
 	googleUserInfoURL = "https://www.googleapis.com/oauth2/v2/userinfo"
 )
 
-type GoogleUser struct {
+type googleUser struct {
 	ID            string `json:"id"`
 	Email         string `json:"email"`
 	VerifiedEmail bool   `json:"verified_email"`
@@ -32,52 +22,39 @@ type GoogleUser struct {
 	Picture       string `json:"picture"`
 }
 
-type GoogleManager struct {
-	config *oauth2.Config
-}
-
-func GenerateState(n int) (string, error) {
-	b := make([]byte, n)
-	if _, err := rand.Read(b); err != nil {
-		return "", err
-	}
-	return base64.URLEncoding.EncodeToString(b), nil
+type googleProvider struct {
+	oauth2Provider
 }
 
-func NewGoogleManager() *GoogleManager {
-	return &GoogleManager{
+func NewGoogleProvider(c Credentials) Provider {
+	return &googleProvider{oauth2Provider{
+		name: "google",
 		config: &oauth2.Config{
-			ClientID:     os.Getenv(envGoogleClientID),
-			ClientSecret: os.Getenv(envGoogleClientSecret),
-			RedirectURL:  os.Getenv(envGoogleRedirectURL),
+			ClientID:     c.ClientID,
+			ClientSecret: c.ClientSecret,
+			RedirectURL:  c.RedirectURL,
 			Scopes:       []string{scopeEmail, scopeProfile},
 			Endpoint:     google.Endpoint,
 		},
-	}
-}
-
-func (m *GoogleManager) GetAuthURL(state string) string {
-	return m.config.AuthCodeURL(state)
+	}}
 }
 
-func (m *GoogleManager) GetUserInfo(ctx context.Context, code string) (*GoogleUser, error) {
-	// Exchange code for token
-	token, err := m.config.Exchange(ctx, code)
-	if err != nil {
-		return nil, fmt.Errorf("code exchange failed: %w", err)
-	}
-
-	// Fetch user info from Google
-	resp, err := http.Get(googleUserInfoURL + "?access_token=" + token.AccessToken)
+func (p *googleProvider) Identify(ctx context.Context, code string, flow Flow) (*UserInfo, error) {
+	token, err := p.exchange(ctx, code, flow)
 	if err != nil {
-		return nil, fmt.Errorf("failed getting user info: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var gUser GoogleUser
-	if err := json.NewDecoder(resp.Body).Decode(&gUser); err != nil {
+	var gUser googleUser
+	if err := getJSON(ctx, googleUserInfoURL, token.AccessToken, &gUser); err != nil {
 		return nil, err
 	}
 
-	return &gUser, nil
-}
\ No newline at end of file
+	return &UserInfo{
+		Subject:       gUser.ID,
+		Email:         gUser.Email,
+		EmailVerified: gUser.VerifiedEmail,
+		Name:          gUser.Name,
+		Picture:       gUser.Picture,
+	}, nil
+}
@@ -0,0 +1,209 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+// Allowed clock skew between us and the issuer when checking ID tokens
+const idTokenLeeway = time.Minute
+
+var idTokenAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// boolClaim accepts both true and "true", which some issuers send for email_verified
+type boolClaim bool
+
+func (b *boolClaim) UnmarshalJSON(data []byte) error {
+	*b = boolClaim(strings.Trim(string(data), `"`) == "true")
+	return nil
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string    `json:"nonce"`
+	AuthorizedParty   string    `json:"azp"`
+	Email             string    `json:"email"`
+	EmailVerified     boolClaim `json:"email_verified"`
+	Name              string    `json:"name"`
+	PreferredUsername string    `json:"preferred_username"`
+	Picture           string    `json:"picture"`
+}
+
+type oidcUserInfo struct {
+	Subject       string    `json:"sub"`
+	Email         string    `json:"email"`
+	EmailVerified boolClaim `json:"email_verified"`
+	Name          string    `json:"name"`
+	Picture       string    `json:"picture"`
+}
+
+// oidcProvider signs in with any OpenID Connect issuer. Endpoints come from
+// the issuer's discovery document, fetched on first use rather than at
+// startup so an unreachable issuer does not keep the API from booting.
+type oidcProvider struct {
+	oauth2Provider
+	issuer string
+
+	mu          sync.Mutex
+	discovered  bool
+	userInfoURL string
+	keys        *keySet
+}
+
+// NewOIDCProvider serves the issuer under name. Scopes default to openid,
+// email and profile.
+func NewOIDCProvider(name, issuer string, c Credentials, scopes []string) Provider {
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+	return &oidcProvider{
+		oauth2Provider: oauth2Provider{
+			name: name,
+			config: &oauth2.Config{
+				ClientID:     c.ClientID,
+				ClientSecret: c.ClientSecret,
+				RedirectURL:  c.RedirectURL,
+				Scopes:       scopes,
+			},
+		},
+		issuer: strings.TrimSuffix(issuer, "/"),
+	}
+}
+
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, flow Flow) (string, error) {
+	if err := p.discover(ctx); err != nil {
+		return "", err
+	}
+	return p.config.AuthCodeURL(flow.State,
+		oauth2.S256ChallengeOption(flow.Verifier),
+		oauth2.SetAuthURLParam("nonce", flow.Nonce),
+	), nil
+}
+
+// Identify trusts the ID token only after checking its signature against
+// the issuer's keys, its issuer, audience, expiry and the flow's nonce. The
+// userinfo endpoint fills in whatever the token left out.
+func (p *oidcProvider) Identify(ctx context.Context, code string, flow Flow) (*UserInfo, error) {
+	if err := p.discover(ctx); err != nil {
+		return nil, err
+	}
+	token, err := p.exchange(ctx, code, flow)
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, _ := token.Extra("id_token").(string)
+	if rawIDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+	claims, err := p.verifyIDToken(ctx, rawIDToken, flow.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &UserInfo{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+		Picture:       claims.Picture,
+	}
+	if info.Name == "" {
+		info.Name = claims.PreferredUsername
+	}
+
+	if info.Email == "" && p.userInfoURL != "" {
+		var ui oidcUserInfo
+		if err := getJSON(ctx, p.userInfoURL, token.AccessToken, &ui); err != nil {
+			return nil, err
+		}
+		// The userinfo response is only about this user if the subjects match
+		if ui.Subject != claims.Subject {
+			return nil, errors.New("userinfo subject does not match id token")
+		}
+		info.Email = ui.Email
+		info.EmailVerified = bool(ui.EmailVerified)
+		if info.Name == "" {
+			info.Name = ui.Name
+		}
+		if info.Picture == "" {
+			info.Picture = ui.Picture
+		}
+	}
+	return info, nil
+}
+
+func (p *oidcProvider) verifyIDToken(ctx context.Context, raw, nonce string) (*idTokenClaims, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(raw, &claims,
+		func(t *jwt.Token) (interface{}, error) {
+			kid, _ := t.Header["kid"].(string)
+			return p.keys.key(ctx, kid)
+		},
+		jwt.WithValidMethods(idTokenAlgorithms),
+		jwt.WithIssuer(p.issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(idTokenLeeway),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("invalid id token: no subject")
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("invalid id token: nonce mismatch")
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.config.ClientID {
+		return nil, errors.New("invalid id token: issued to another party")
+	}
+	return &claims, nil
+}
+
+// discover loads the discovery document once; a failed attempt is retried on the next call
+func (p *oidcProvider) discover(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovered {
+		return nil
+	}
+
+	var doc discoveryDocument
+	if err := getJSON(ctx, p.issuer+"/.well-known/openid-configuration", "", &doc); err != nil {
+		return fmt.Errorf("oidc discovery for %s: %w", p.name, err)
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != p.issuer {
+		return fmt.Errorf("oidc discovery for %s: issuer %q does not match %q", p.name, doc.Issuer, p.issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return fmt.Errorf("oidc discovery for %s: incomplete discovery document", p.name)
+	}
+
+	// ID tokens carry the issuer exactly as the document spells it
+	p.issuer = doc.Issuer
+	p.config.Endpoint = oauth2.Endpoint{
+		AuthURL:  doc.AuthorizationEndpoint,
+		TokenURL: doc.TokenEndpoint,
+	}
+	p.userInfoURL = doc.UserInfoEndpoint
+	p.keys = newKeySet(doc.JWKSURI)
+	p.discovered = true
+	return nil
+}
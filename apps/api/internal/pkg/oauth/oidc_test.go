@@ -0,0 +1,340 @@
+package oauth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testClientID = "filmophilia"
+
+// fakeIssuer is a minimal OpenID Connect issuer. Its token endpoint records
+// the PKCE verifier it was sent and answers with an ID token built from
+// the claims set by the test.
+type fakeIssuer struct {
+	*httptest.Server
+	key ed25519.PrivateKey
+	// issuer overrides the one the discovery document names
+	issuer string
+
+	mu       sync.Mutex
+	claims   jwt.MapClaims
+	verifier string
+	userInfo map[string]any
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeIssuer{key: priv}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		issuer := f.URL
+		if f.issuer != "" {
+			issuer = f.issuer
+		}
+		writeTestJSON(w, discoveryDocument{
+			Issuer:                issuer,
+			AuthorizationEndpoint: f.URL + "/authorize",
+			TokenEndpoint:         f.URL + "/token",
+			UserInfoEndpoint:      f.URL + "/userinfo",
+			JWKSURI:               f.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, map[string]any{"keys": []jsonWebKey{{
+			Kty: "OKP",
+			Kid: "test-key",
+			Use: "sig",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+		}}})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.verifier = r.PostForm.Get("code_verifier")
+
+		idToken := jwt.NewWithClaims(jwt.SigningMethodEdDSA, f.claims)
+		idToken.Header["kid"] = "test-key"
+		signed, err := idToken.SignedString(f.key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeTestJSON(w, map[string]any{
+			"access_token": "access-" + r.PostForm.Get("code"),
+			"token_type":   "Bearer",
+			"expires_in":   300,
+			"id_token":     signed,
+		})
+	})
+	mux.HandleFunc("GET /userinfo", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		writeTestJSON(w, f.userInfo)
+	})
+
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func writeTestJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// validClaims is an ID token the provider accepts for flow
+func (f *fakeIssuer) validClaims(flow Flow) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            f.URL,
+		"sub":            "subject-1",
+		"aud":            testClientID,
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          flow.Nonce,
+		"email":          "ada@example.com",
+		"email_verified": true,
+		"name":           "Ada",
+	}
+}
+
+func (f *fakeIssuer) provider() Provider {
+	return NewOIDCProvider("dev", f.URL, Credentials{
+		ClientID:     testClientID,
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:8080/api/v1/auth/dev/callback",
+	}, nil)
+}
+
+func newTestFlow(t *testing.T) Flow {
+	t.Helper()
+	flow, err := NewFlow("dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return flow
+}
+
+func TestOIDCAuthCodeURL(t *testing.T) {
+	f := newFakeIssuer(t)
+	flow := newTestFlow(t)
+
+	raw, err := f.provider().AuthCodeURL(context.Background(), flow)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256([]byte(flow.Verifier))
+	q := u.Query()
+	checks := map[string]string{
+		"endpoint":              u.Scheme + "://" + u.Host + u.Path,
+		"client_id":             q.Get("client_id"),
+		"state":                 q.Get("state"),
+		"nonce":                 q.Get("nonce"),
+		"code_challenge":        q.Get("code_challenge"),
+		"code_challenge_method": q.Get("code_challenge_method"),
+		"scope":                 q.Get("scope"),
+	}
+	want := map[string]string{
+		"endpoint":              f.URL + "/authorize",
+		"client_id":             testClientID,
+		"state":                 flow.State,
+		"nonce":                 flow.Nonce,
+		"code_challenge":        base64.RawURLEncoding.EncodeToString(sum[:]),
+		"code_challenge_method": "S256",
+		"scope":                 "openid email profile",
+	}
+	for k, v := range want {
+		if checks[k] != v {
+			t.Errorf("%s = %q, want %q", k, checks[k], v)
+		}
+	}
+	if q.Has("code_verifier") {
+		t.Error("the PKCE verifier leaked into the authorization URL")
+	}
+}
+
+func TestOIDCDiscoveryIssuerMismatch(t *testing.T) {
+	f := newFakeIssuer(t)
+	f.issuer = "https://accounts.example.com"
+
+	_, err := f.provider().AuthCodeURL(context.Background(), newTestFlow(t))
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("AuthCodeURL error = %v, want an issuer mismatch", err)
+	}
+}
+
+func TestOIDCIdentify(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c jwt.MapClaims)
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			modify: func(jwt.MapClaims) {},
+		},
+		{
+			name: "wrong nonce",
+			modify: func(c jwt.MapClaims) {
+				c["nonce"] = "replayed"
+			},
+			wantErr: "nonce mismatch",
+		},
+		{
+			name: "missing nonce",
+			modify: func(c jwt.MapClaims) {
+				delete(c, "nonce")
+			},
+			wantErr: "nonce mismatch",
+		},
+		{
+			name: "wrong audience",
+			modify: func(c jwt.MapClaims) {
+				c["aud"] = "another-client"
+			},
+			wantErr: "audience",
+		},
+		{
+			name: "wrong issuer",
+			modify: func(c jwt.MapClaims) {
+				c["iss"] = "https://evil.example.com"
+			},
+			wantErr: "issuer",
+		},
+		{
+			name: "several audiences without azp",
+			modify: func(c jwt.MapClaims) {
+				c["aud"] = []string{testClientID, "another-client"}
+			},
+			wantErr: "another party",
+		},
+		{
+			name: "several audiences with another azp",
+			modify: func(c jwt.MapClaims) {
+				c["aud"] = []string{testClientID, "another-client"}
+				c["azp"] = "another-client"
+			},
+			wantErr: "another party",
+		},
+		{
+			name: "several audiences with our azp",
+			modify: func(c jwt.MapClaims) {
+				c["aud"] = []string{testClientID, "another-client"}
+				c["azp"] = testClientID
+			},
+		},
+		{
+			name: "expired",
+			modify: func(c jwt.MapClaims) {
+				c["exp"] = time.Now().Add(-2 * idTokenLeeway).Unix()
+			},
+			wantErr: "expired",
+		},
+		{
+			name: "no subject",
+			modify: func(c jwt.MapClaims) {
+				delete(c, "sub")
+			},
+			wantErr: "no subject",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeIssuer(t)
+			flow := newTestFlow(t)
+			f.claims = f.validClaims(flow)
+			tt.modify(f.claims)
+
+			info, err := f.provider().Identify(context.Background(), "the-code", flow)
+
+			if f.verifier != flow.Verifier {
+				t.Errorf("token endpoint got verifier %q, want %q", f.verifier, flow.Verifier)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Identify error = %v, want one mentioning %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Identify: %v", err)
+			}
+			want := UserInfo{Subject: "subject-1", Email: "ada@example.com", EmailVerified: true, Name: "Ada"}
+			if *info != want {
+				t.Errorf("info = %+v, want %+v", *info, want)
+			}
+		})
+	}
+}
+
+func TestOIDCIdentifyUserInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		userInfo map[string]any
+		want     *UserInfo
+	}{
+		{
+			name:     "fills in what the id token left out",
+			userInfo: map[string]any{"sub": "subject-1", "email": "ada@example.com", "email_verified": "true", "picture": "https://example.com/ada.png"},
+			want:     &UserInfo{Subject: "subject-1", Email: "ada@example.com", EmailVerified: true, Name: "Ada", Picture: "https://example.com/ada.png"},
+		},
+		{
+			name:     "about another subject",
+			userInfo: map[string]any{"sub": "subject-2", "email": "eve@example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeIssuer(t)
+			flow := newTestFlow(t)
+			f.claims = f.validClaims(flow)
+			delete(f.claims, "email")
+			delete(f.claims, "email_verified")
+			f.userInfo = tt.userInfo
+
+			info, err := f.provider().Identify(context.Background(), "the-code", flow)
+			if tt.want == nil {
+				if err == nil {
+					t.Fatalf("Identify = %+v, want an error", info)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Identify: %v", err)
+			}
+			if *info != *tt.want {
+				t.Errorf("info = %+v, want %+v", *info, *tt.want)
+			}
+		})
+	}
+}
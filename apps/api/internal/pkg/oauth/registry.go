@@ -0,0 +1,128 @@
+package oauth
+
+import (
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// Comma separated names of generic OpenID Connect providers, each
+	// configured with OIDC_<NAME>_ISSUER, OIDC_<NAME>_CLIENT_ID, ...
+	envOIDCProviders = "OIDC_PROVIDERS"
+	// Where callbacks land when a provider has no <NAME>_REDIRECT_URL
+	envCallbackBaseURL = "OAUTH_CALLBACK_BASE_URL"
+
+	defaultCallbackBaseURL = "http://localhost:8080/api/v1/auth"
+)
+
+var providerName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Credentials is what the application is registered with at a provider
+type Credentials struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+// Registry holds the enabled providers by name
+type Registry struct {
+	providers map[string]Provider
+}
+
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: make(map[string]Provider, len(providers))}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+	}
+	return r
+}
+
+func (r *Registry) Get(name string) (Provider, bool) {
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// Names lists the enabled providers in alphabetical order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// NewRegistryFromEnv enables every provider whose client id is set:
+// GOOGLE_CLIENT_ID, GITHUB_CLIENT_ID and DISCORD_CLIENT_ID with the matching
+// _CLIENT_SECRET and _REDIRECT_URL, and each OIDC_PROVIDERS entry, e.g.
+//
+//	OIDC_PROVIDERS=keycloak
+//	OIDC_KEYCLOAK_ISSUER=https://sso.example.com/realms/main
+//	OIDC_KEYCLOAK_CLIENT_ID=filmophilia
+//	OIDC_KEYCLOAK_CLIENT_SECRET=...
+//	OIDC_KEYCLOAK_SCOPES=openid email profile (optional)
+func NewRegistryFromEnv() *Registry {
+	var providers []Provider
+
+	builtin := []struct {
+		name string
+		new  func(Credentials) Provider
+	}{
+		{"google", NewGoogleProvider},
+		{"github", NewGitHubProvider},
+		{"discord", NewDiscordProvider},
+	}
+	for _, b := range builtin {
+		if c, ok := credentialsFromEnv(strings.ToUpper(b.name), b.name); ok {
+			providers = append(providers, b.new(c))
+		}
+	}
+
+	for _, name := range strings.Split(os.Getenv(envOIDCProviders), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !providerName.MatchString(name) || slices.ContainsFunc(providers, func(p Provider) bool { return p.Name() == name }) {
+			log.Printf("ignoring oidc provider %q: invalid or duplicate name", name)
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		issuer := os.Getenv(prefix + "_ISSUER")
+		c, ok := credentialsFromEnv(prefix, name)
+		if !ok || issuer == "" {
+			log.Printf("ignoring oidc provider %q: %s_ISSUER and %s_CLIENT_ID are required", name, prefix, prefix)
+			continue
+		}
+		scopes := strings.Fields(strings.ReplaceAll(os.Getenv(prefix+"_SCOPES"), ",", " "))
+		providers = append(providers, NewOIDCProvider(name, issuer, c, scopes))
+	}
+
+	r := NewRegistry(providers...)
+	if names := r.Names(); len(names) > 0 {
+		log.Printf("oauth providers: %s", strings.Join(names, ", "))
+	}
+	return r
+}
+
+func credentialsFromEnv(prefix, name string) (Credentials, bool) {
+	c := Credentials{
+		ClientID:     os.Getenv(prefix + "_CLIENT_ID"),
+		ClientSecret: os.Getenv(prefix + "_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv(prefix + "_REDIRECT_URL"),
+	}
+	if c.ClientID == "" {
+		return Credentials{}, false
+	}
+	if c.RedirectURL == "" {
+		base := os.Getenv(envCallbackBaseURL)
+		if base == "" {
+			base = defaultCallbackBaseURL
+		}
+		c.RedirectURL = strings.TrimSuffix(base, "/") + "/" + name + "/callback"
+	}
+	return c, true
+}
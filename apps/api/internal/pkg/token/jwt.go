@@ -1,7 +1,12 @@
 package token
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -10,11 +15,58 @@ import (
 const (
 	AccessTokenDuration  = 15 * time.Minute
 	RefreshTokenDuration = 7 * 24 * time.Hour
+
+	EmailVerificationDuration = 24 * time.Hour
+)
+
+// Purpose tokens are signed with a key derived from the secret and the purpose,
+// so they can never pass Verify as an access token or be used for another purpose
+const (
+	PurposeVerifyEmail = "verify_email"
+	PurposeLinkAccount = "link_account"
 )
 
+// Audience of access tokens. Services verifying them against the JWKS should
+// require it, so tokens minted for something else are not accepted.
+const AccessTokenAudience = "filmophilia-api"
+
+// Claims are the claims of an access token
+type Claims struct {
+	jwt.RegisteredClaims
+	Role      string `json:"role"`
+	SessionID string `json:"sid,omitempty"`
+}
+
+// Validate rejects tokens that are validly signed but malformed, so the
+// accessors never see garbage. The parser calls it after the standard checks.
+func (c *Claims) Validate() error {
+	id, err := strconv.ParseInt(c.Subject, 10, 32)
+	if err != nil || id <= 0 {
+		return fmt.Errorf("invalid token subject")
+	}
+	if c.Role == "" || c.ID == "" {
+		return fmt.Errorf("incomplete token claims")
+	}
+	return nil
+}
+
+// UserID is the subject as a user id; Validate has checked it parses
+func (c *Claims) UserID() int32 {
+	id, _ := strconv.ParseInt(c.Subject, 10, 32)
+	return int32(id)
+}
+
+// JWTManager signs access tokens with HS256 and the secret, or with an
+// asymmetric key when one is configured. Purpose tokens always use keys
+// derived from the secret: they never leave this service.
 type JWTManager struct {
 	secretKey string
 	issuer    string
+
+	signing *Key
+	// Keys accepted for access tokens by kid: the signing key and the keys it
+	// replaced, until tokens signed with them have expired
+	verifying map[string]Key
 }
 
 func NewJWTManager(secret string) *JWTManager {
@@ -24,37 +76,157 @@ func NewJWTManager(secret string) *JWTManager {
 	}
 }
 
-// Generate creates a new JWT for a specific user
-func (m *JWTManager) Generate(userID int32, role string, duration time.Duration) (string, error) {
+// NewJWTManagerWithKeys signs access tokens with signing and also accepts
+// tokens signed with previous, which makes rotation a two-step deploy: add the
+// new key as the signing key and keep the old one in previous until the last
+// token it signed has expired.
+func NewJWTManagerWithKeys(secret string, signing Key, previous ...Key) (*JWTManager, error) {
+	if !signing.CanSign() {
+		return nil, fmt.Errorf("signing key %s has no private key", signing.ID)
+	}
+	m := NewJWTManager(secret)
+	m.signing = &signing
+	m.verifying = map[string]Key{signing.ID: signing}
+	for _, k := range previous {
+		m.verifying[k.ID] = k
+	}
+	return m, nil
+}
+
+// PublicKeys lists the keys access tokens may be signed with, for the JWKS
+// endpoint. It is empty when tokens are signed with the shared secret.
+func (m *JWTManager) PublicKeys() []JWK {
+	if m.signing == nil {
+		return []JWK{}
+	}
+	keys := []JWK{m.signing.JWK()}
+	for id, k := range m.verifying {
+		if id != m.signing.ID {
+			keys = append(keys, k.JWK())
+		}
+	}
+	return keys
+}
+
+// Generate creates a new JWT for a specific user, bound to the session it was issued with
+func (m *JWTManager) Generate(userID int32, role, sessionID string, duration time.Duration) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.issuer,
+			Subject:   strconv.FormatInt(int64(userID), 10),
+			Audience:  jwt.ClaimStrings{AccessTokenAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        jti,
+		},
+		Role:      role,
+		SessionID: sessionID,
+	}
+
+	if m.signing == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(m.secretKey))
+	}
+	token := jwt.NewWithClaims(m.signing.method, claims)
+	token.Header["kid"] = m.signing.ID
+	return token.SignedString(m.signing.private)
+}
+
+// Verify validates an access token and returns its claims
+func (m *JWTManager) Verify(tokenStr string) (*Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(tokenStr, &claims, m.accessKey,
+		jwt.WithIssuer(m.issuer),
+		jwt.WithAudience(AccessTokenAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// GeneratePurpose creates a short-lived token bound to a user and email address
+func (m *JWTManager) GeneratePurpose(purpose string, userID int32, email string, duration time.Duration) (string, error) {
 	claims := jwt.MapClaims{
-		"sub":  userID,
-		"role": role,
-		"exp":  time.Now().Add(duration).Unix(),
-		"iat":  time.Now().Unix(),
-		"iss":  m.issuer,
+		"sub":     userID,
+		"email":   email,
+		"purpose": purpose,
+		"exp":     time.Now().Add(duration).Unix(),
+		"iat":     time.Now().Unix(),
+		"iss":     m.issuer,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(m.secretKey))
+	return token.SignedString(m.purposeKey(purpose))
 }
 
-// Verify validates the token and returns the claims
-func (m *JWTManager) Verify(tokenStr string) (jwt.MapClaims, error) {
+// VerifyPurpose validates a token made by GeneratePurpose and returns the user and email it was issued for
+func (m *JWTManager) VerifyPurpose(purpose, tokenStr string) (int32, string, error) {
 	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 		}
-		return []byte(m.secretKey), nil
-	})
-
+		return m.purposeKey(purpose), nil
+	}, jwt.WithIssuer(m.issuer), jwt.WithExpirationRequired())
 	if err != nil {
-		return nil, err
+		return 0, "", err
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok || !token.Valid {
-		return nil, fmt.Errorf("invalid token")
+	if !ok || !token.Valid || claims["purpose"] != purpose {
+		return 0, "", fmt.Errorf("invalid token")
+	}
+	sub, ok := claims["sub"].(float64)
+	if !ok {
+		return 0, "", fmt.Errorf("invalid token subject")
+	}
+	email, ok := claims["email"].(string)
+	if !ok {
+		return 0, "", fmt.Errorf("invalid token email")
+	}
+
+	return int32(sub), email, nil
+}
+
+// accessKey picks the key an access token must verify with. Once asymmetric
+// keys are configured HS256 tokens are refused, so the secret alone can no
+// longer mint access tokens.
+func (m *JWTManager) accessKey(t *jwt.Token) (interface{}, error) {
+	if m.signing == nil {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return []byte(m.secretKey), nil
+	}
+
+	kid, _ := t.Header["kid"].(string)
+	k, ok := m.verifying[kid]
+	if !ok {
+		return nil, errUnknownKey
+	}
+	if t.Method.Alg() != k.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 	}
+	return k.public, nil
+}
 
-	return claims, nil
-}
\ No newline at end of file
+// newTokenID returns a random jti
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (m *JWTManager) purposeKey(purpose string) []byte {
+	mac := hmac.New(sha256.New, []byte(m.secretKey))
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,100 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signedClaims is a token Verify accepts, with modify applied to its claims
+// before signing
+func signedClaims(t *testing.T, secret string, modify func(c *Claims)) string {
+	t.Helper()
+	now := time.Now()
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "filmophilia",
+			Subject:   "42",
+			Audience:  jwt.ClaimStrings{AccessTokenAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        "token-1",
+		},
+		Role:      "USER",
+		SessionID: "session-1",
+	}
+	modify(&claims)
+	s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// Every token here is validly signed, so only Claims.Validate stands between
+// a malformed one and the accessors that used to panic on it
+func TestVerifyValidatesClaims(t *testing.T) {
+	const secret = "test-secret"
+	m := NewJWTManager(secret)
+
+	tests := []struct {
+		name    string
+		modify  func(c *Claims)
+		wantErr bool
+	}{
+		{"valid", func(*Claims) {}, false},
+		{"no session is allowed", func(c *Claims) { c.SessionID = "" }, false},
+		{"empty subject", func(c *Claims) { c.Subject = "" }, true},
+		{"non-numeric subject", func(c *Claims) { c.Subject = "admin" }, true},
+		{"fractional subject", func(c *Claims) { c.Subject = "4.2" }, true},
+		{"zero subject", func(c *Claims) { c.Subject = "0" }, true},
+		{"negative subject", func(c *Claims) { c.Subject = "-42" }, true},
+		{"subject overflows int32", func(c *Claims) { c.Subject = "2147483648" }, true},
+		{"empty role", func(c *Claims) { c.Role = "" }, true},
+		{"empty jti", func(c *Claims) { c.ID = "" }, true},
+		{"wrong audience", func(c *Claims) { c.Audience = jwt.ClaimStrings{"another-api"} }, true},
+		{"no expiry", func(c *Claims) { c.ExpiresAt = nil }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := m.Verify(signedClaims(t, secret, tt.modify))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Verify accepted claims %+v", claims)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if claims.UserID() != 42 || claims.Role != "USER" {
+				t.Errorf("claims = %+v, want user 42 with role USER", claims)
+			}
+		})
+	}
+}
+
+func TestGenerateVerifyRoundTrip(t *testing.T) {
+	m := NewJWTManager("test-secret")
+	s, err := m.Generate(7, "ADMIN", "session-1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := m.Verify(s)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if claims.UserID() != 7 || claims.Role != "ADMIN" || claims.SessionID != "session-1" || claims.ID == "" {
+		t.Errorf("claims = %+v, want user 7, ADMIN, session-1 and a jti", claims)
+	}
+	// Purpose tokens are signed with other keys and never pass as access tokens
+	link, err := m.GeneratePurpose(PurposeLinkAccount, 7, "", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Verify(link); err == nil {
+		t.Error("a purpose token passed as an access token")
+	}
+}
@@ -0,0 +1,146 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Smallest RSA modulus accepted for signing keys
+const minRSABits = 2048
+
+// Key is an asymmetric JWT key. Its id is the RFC 7638 thumbprint of the
+// public key, so a key keeps the same kid wherever it is loaded and nothing
+// has to be configured to match tokens to keys during a rotation.
+type Key struct {
+	ID      string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// JWK is a public key as published at /.well-known/jwks.json
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// LoadKey reads an RSA or Ed25519 key from a PEM file. Private keys sign with
+// RS256 or EdDSA; public keys can only verify.
+func LoadKey(path string) (Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, fmt.Errorf("%s: no PEM data", path)
+	}
+
+	var parsed any
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return Key{}, fmt.Errorf("%s: unsupported PEM block %q", path, block.Type)
+	}
+	if err != nil {
+		return Key{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	key, err := newKey(parsed)
+	if err != nil {
+		return Key{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+func newKey(parsed any) (Key, error) {
+	var k Key
+	switch v := parsed.(type) {
+	case *rsa.PrivateKey:
+		k = Key{method: jwt.SigningMethodRS256, private: v, public: &v.PublicKey}
+	case *rsa.PublicKey:
+		k = Key{method: jwt.SigningMethodRS256, public: v}
+	case ed25519.PrivateKey:
+		k = Key{method: jwt.SigningMethodEdDSA, private: v, public: v.Public()}
+	case ed25519.PublicKey:
+		k = Key{method: jwt.SigningMethodEdDSA, public: v}
+	default:
+		return Key{}, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", parsed)
+	}
+	if pub, ok := k.public.(*rsa.PublicKey); ok && pub.N.BitLen() < minRSABits {
+		return Key{}, fmt.Errorf("rsa key is %d bits, at least %d are required", pub.N.BitLen(), minRSABits)
+	}
+
+	jwk := k.JWK()
+	k.ID = jwk.thumbprint()
+	return k, nil
+}
+
+func (k Key) CanSign() bool {
+	return k.private != nil
+}
+
+// JWK returns the public half of the key
+func (k Key) JWK() JWK {
+	jwk := JWK{Kid: k.ID, Use: "sig", Alg: k.method.Alg()}
+	switch pub := k.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+	return jwk
+}
+
+// thumbprint hashes the required members in lexicographic order (RFC 7638)
+func (j JWK) thumbprint() string {
+	var members any
+	switch j.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{j.E, j.Kty, j.N}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{j.Crv, j.Kty, j.X}
+	}
+	b, _ := json.Marshal(members)
+	sum := sha256.Sum256(b)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+var errUnknownKey = errors.New("unknown signing key")
@@ -0,0 +1,184 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestJWKThumbprint(t *testing.T) {
+	tests := []struct {
+		name string
+		jwk  JWK
+		want string
+	}{
+		{
+			// RFC 7638, section 3.1
+			name: "rsa",
+			jwk: JWK{
+				Kty: "RSA",
+				N:   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+				E:   "AQAB",
+				// Optional members are not part of the thumbprint
+				Kid: "2011-04-29",
+				Alg: "RS256",
+				Use: "sig",
+			},
+			want: "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs",
+		},
+		{
+			// RFC 8037, appendix A.3
+			name: "ed25519",
+			jwk: JWK{
+				Kty: "OKP",
+				Crv: "Ed25519",
+				X:   "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo",
+			},
+			want: "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.jwk.thumbprint(); got != tt.want {
+				t.Errorf("thumbprint = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// A key has the same id whether its private or public half is loaded, so
+// verifiers holding only the public key match the signer's kid
+func TestKeyIDMatchesPublicHalf(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	private, err := newKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := newKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if private.ID == "" || private.ID != public.ID {
+		t.Errorf("private kid %q, public kid %q; want the same", private.ID, public.ID)
+	}
+	if private.JWK().Kid != private.ID {
+		t.Errorf("JWK kid = %q, want %q", private.JWK().Kid, private.ID)
+	}
+	if public.CanSign() {
+		t.Error("a public key claims it can sign")
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	const secret = "test-secret"
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, minRSABits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldKey, err := newKey(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newSigningKey, err := newKey(edKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Verifiers only need the public half of the key being retired
+	oldPublic, err := newKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := NewJWTManagerWithKeys(secret, oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	during, err := NewJWTManagerWithKeys(secret, newSigningKey, oldPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := NewJWTManagerWithKeys(secret, newSigningKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldToken, err := before.Generate(1, "USER", "session-1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newToken, err := during.Generate(1, "USER", "session-2", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hsToken, err := NewJWTManager(secret).Generate(1, "USER", "session-3", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The new token is signed with the new key and names it
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &Claims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Header["kid"] != newSigningKey.ID || parsed.Method.Alg() != "EdDSA" {
+		t.Errorf("new token header = %v, want kid %q with EdDSA", parsed.Header, newSigningKey.ID)
+	}
+
+	tests := []struct {
+		name    string
+		m       *JWTManager
+		token   string
+		wantErr bool
+	}{
+		{"old key verifies its own token", before, oldToken, false},
+		{"new key verifies its own token", during, newToken, false},
+		{"old token is accepted during the rotation", during, oldToken, false},
+		{"old token is refused once the old key is dropped", after, oldToken, true},
+		{"new token is refused where the new key is unknown", before, newToken, true},
+		{"shared secret tokens are refused once keys are configured", during, hsToken, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := tt.m.Verify(tt.token)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Verify accepted the token")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if claims.UserID() != 1 {
+				t.Errorf("user id = %d, want 1", claims.UserID())
+			}
+		})
+	}
+
+	if _, err := after.Verify(oldToken); !errors.Is(err, errUnknownKey) {
+		t.Errorf("Verify error = %v, want errUnknownKey", err)
+	}
+
+	keys := during.PublicKeys()
+	if len(keys) != 2 || keys[0].Kid != newSigningKey.ID || keys[1].Kid != oldKey.ID {
+		t.Errorf("published keys = %+v, want the new key then the old one", keys)
+	}
+	if _, err := NewJWTManagerWithKeys(secret, oldPublic); err == nil {
+		t.Error("a public key was accepted as the signing key")
+	}
+}
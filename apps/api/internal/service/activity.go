@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+)
+
+// recordActivity appends to the actor's activity stream. Like notifications it
+// is best effort: the action itself has already succeeded and must not fail
+// because its feed entry could not be written.
+func recordActivity(ctx context.Context, q *db.Queries, userID int32, action string, entityType db.EntityType, entityID int32, metadata map[string]any) {
+	var raw []byte
+	if metadata != nil {
+		raw, _ = json.Marshal(metadata)
+	}
+
+	if err := q.CreateActivity(ctx, db.CreateActivityParams{
+		UserID:     userID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Metadata:   raw,
+	}); err != nil {
+		log.Printf("record %s activity for user %d: %v", action, userID, err)
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/mapper"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Shortest moderation reason accepted, counted after trimming whitespace
+const minModerationReasonLen = 3
+
+var (
+	ErrReasonRequired          = errors.New("a reason of at least 3 characters is required")
+	ErrInvalidStatusTransition = errors.New("this action does not apply to the user's current status")
+	ErrCannotModerateSelf      = errors.New("you cannot change your own status")
+	ErrCannotModerateAdmin     = errors.New("admin accounts cannot be moderated")
+)
+
+// statusTransition is the status a moderation action sets and the statuses it may be applied from
+type statusTransition struct {
+	to   db.UserStatus
+	from []db.UserStatus
+}
+
+var moderationTransitions = map[string]statusTransition{
+	dto.ModerationActivate: {to: db.UserStatusACTIVE, from: []db.UserStatus{db.UserStatusPENDING}},
+	dto.ModerationSuspend:  {to: db.UserStatusSUSPENDED, from: []db.UserStatus{db.UserStatusPENDING, db.UserStatusACTIVE}},
+	dto.ModerationBan:      {to: db.UserStatusBANNED, from: []db.UserStatus{db.UserStatusPENDING, db.UserStatusACTIVE, db.UserStatusSUSPENDED}},
+	dto.ModerationUnban:    {to: db.UserStatusACTIVE, from: []db.UserStatus{db.UserStatusSUSPENDED, db.UserStatusBANNED}},
+}
+
+type AdminService struct {
+	pool          *pgxpool.Pool
+	queries       *db.Queries
+	notifications *NotificationService
+}
+
+func NewAdminService(pool *pgxpool.Pool, q *db.Queries, ns *NotificationService) *AdminService {
+	return &AdminService{pool: pool, queries: q, notifications: ns}
+}
+
+func (s *AdminService) GetUser(ctx context.Context, userID int32) (*dto.AdminUserResponse, error) {
+	user, err := getUserByID(ctx, s.queries, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := mapper.ToAdminUserResponse(user)
+	return &resp, nil
+}
+
+// Moderate applies a moderation action. The status trigger records the reason
+// and acting admin; suspending or banning also signs the user out everywhere.
+func (s *AdminService) Moderate(ctx context.Context, adminID, userID int32, action, reason string) (*dto.AdminUserResponse, error) {
+	transition, ok := moderationTransitions[action]
+	if !ok {
+		return nil, ErrInvalidStatusTransition
+	}
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) < minModerationReasonLen {
+		return nil, ErrReasonRequired
+	}
+	if adminID == userID {
+		return nil, ErrCannotModerateSelf
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.queries.WithTx(tx)
+
+	user, err := getUserByID(ctx, qtx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == db.RoleADMIN {
+		return nil, ErrCannotModerateAdmin
+	}
+	if !slices.Contains(transition.from, user.Status) {
+		return nil, ErrInvalidStatusTransition
+	}
+
+	if err := qtx.SetStatusChangeContext(ctx, db.SetStatusChangeContextParams{Reason: reason, ChangedBy: adminID}); err != nil {
+		return nil, err
+	}
+	if err := qtx.UpdateUserStatus(ctx, db.UpdateUserStatusParams{ID: userID, Status: transition.to}); err != nil {
+		return nil, err
+	}
+	if transition.to == db.UserStatusSUSPENDED || transition.to == db.UserStatusBANNED {
+		if err := qtx.DeleteUserSessions(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.notifications.NotifyAccountStatus(ctx, userID, transition.to, reason)
+
+	user.Status = transition.to
+	resp := mapper.ToAdminUserResponse(user)
+	return &resp, nil
+}
+
+func (s *AdminService) StatusHistory(ctx context.Context, userID int32, q dto.PaginationQuery) (dto.PaginatedResponse[dto.StatusLogResponse], error) {
+	if _, err := getUserByID(ctx, s.queries, userID); err != nil {
+		return dto.PaginatedResponse[dto.StatusLogResponse]{}, err
+	}
+
+	total, err := s.queries.CountUserStatusLogs(ctx, userID)
+	if err != nil {
+		return dto.PaginatedResponse[dto.StatusLogResponse]{}, err
+	}
+
+	rows, err := s.queries.ListUserStatusLogs(ctx, db.ListUserStatusLogsParams{UserID: userID, Limit: q.Limit(), Offset: q.Offset()})
+	if err != nil {
+		return dto.PaginatedResponse[dto.StatusLogResponse]{}, err
+	}
+
+	return dto.NewPaginatedResponse(mapper.ToStatusLogResponses(rows), q, total), nil
+}
+
+func (s *AdminService) SecurityEvents(ctx context.Context, userID int32, q dto.PaginationQuery) (dto.PaginatedResponse[dto.SecurityEventResponse], error) {
+	if _, err := getUserByID(ctx, s.queries, userID); err != nil {
+		return dto.PaginatedResponse[dto.SecurityEventResponse]{}, err
+	}
+
+	total, err := s.queries.CountUserSecurityEvents(ctx, userID)
+	if err != nil {
+		return dto.PaginatedResponse[dto.SecurityEventResponse]{}, err
+	}
+
+	rows, err := s.queries.ListUserSecurityEvents(ctx, db.ListUserSecurityEventsParams{UserID: userID, Limit: q.Limit(), Offset: q.Offset()})
+	if err != nil {
+		return dto.PaginatedResponse[dto.SecurityEventResponse]{}, err
+	}
+
+	return dto.NewPaginatedResponse(mapper.ToSecurityEventResponses(rows), q, total), nil
+}
+
+// ListSessions shows support which devices a user is signed in on
+func (s *AdminService) ListSessions(ctx context.Context, userID int32) ([]dto.SessionResponse, error) {
+	if _, err := getUserByID(ctx, s.queries, userID); err != nil {
+		return nil, err
+	}
+	sessions, err := s.queries.ListUserSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ToSessionResponses(sessions, ""), nil
+}
+
+// RevokeSession signs a user out of one device, e.g. one they reported lost
+func (s *AdminService) RevokeSession(ctx context.Context, userID int32, sessionID string) error {
+	n, err := s.queries.DeleteUserSession(ctx, db.DeleteUserSessionParams{ID: sessionID, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+func getUserByID(ctx context.Context, q *db.Queries, id int32) (db.User, error) {
+	user, err := q.GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.User{}, ErrUserNotFound
+		}
+		return db.User{}, err
+	}
+	return user, nil
+}
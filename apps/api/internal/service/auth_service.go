@@ -2,17 +2,23 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"log"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/MassoudJavadi/filmophilia/api/internal/db"
 	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
 	"github.com/MassoudJavadi/filmophilia/api/internal/mapper"
+	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/mailer"
 	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/token"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -22,15 +28,46 @@ var (
 	ErrEmailExists        = errors.New("email already exists")
 	ErrUsernameExists     = errors.New("username already exists")
 	ErrUserBanned         = errors.New("user is banned")
+	ErrUserSuspended      = errors.New("user is suspended")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrEmailNotVerified   = errors.New("email address is not verified")
+	ErrInvalidVerifyToken = errors.New("invalid or expired verification token")
 )
 
+const (
+	// Minimum gap between two verification or password reset emails to the same account
+	verificationResendCooldown = time.Minute
+	passwordResetCooldown      = time.Minute
+
+	// A rotated-out refresh token presented again within this long is treated
+	// as a concurrent refresh rather than theft
+	refreshReuseGrace = 10 * time.Second
+
+	// Time allowed for handing a message to the mailer after the request has returned
+	mailSendTimeout = 30 * time.Second
+)
+
+// AuthConfig holds the deployment-specific parts of the auth flow
+type AuthConfig struct {
+	// AllowUnverifiedLogin lets PENDING accounts sign in before confirming their email
+	AllowUnverifiedLogin bool
+	// VerifyURL is the frontend page that posts the token to /auth/verify;
+	// the token is appended as the "token" query parameter
+	VerifyURL string
+	// ResetURL is the frontend page that posts the token to /auth/password/reset
+	ResetURL string
+}
+
 type AuthService struct {
+	pool    *pgxpool.Pool
 	queries *db.Queries
 	jwt     *token.JWTManager
+	mailer  mailer.Mailer
+	config  AuthConfig
 }
 
-func NewAuthService(q *db.Queries, j *token.JWTManager) *AuthService {
-	return &AuthService{queries: q, jwt: j}
+func NewAuthService(pool *pgxpool.Pool, q *db.Queries, j *token.JWTManager, m mailer.Mailer, cfg AuthConfig) *AuthService {
+	return &AuthService{pool: pool, queries: q, jwt: j, mailer: m, config: cfg}
 }
 
 func (s *AuthService) Signup(ctx context.Context, req dto.SignupRequest) (db.User, error) {
@@ -42,7 +79,7 @@ func (s *AuthService) Signup(ctx context.Context, req dto.SignupRequest) (db.Use
 	user, err := s.queries.CreateUser(ctx, db.CreateUserParams{
 		Email:        req.Email,
 		Username:     req.Username,
-		PasswordHash: string(hash),
+		PasswordHash: pgtype.Text{String: string(hash), Valid: true},
 	})
 	if err != nil {
 		if strings.Contains(err.Error(), "users_email_key") {
@@ -54,29 +91,46 @@ func (s *AuthService) Signup(ctx context.Context, req dto.SignupRequest) (db.Use
 		return db.User{}, err
 	}
 
+	s.sendVerification(ctx, user)
 	return user, nil
 }
 
-func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest) (*dto.AuthResponse, error) {
+func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest, client dto.ClientInfo) (*dto.AuthResponse, error) {
 	user, err := s.queries.GetUserByEmail(ctx, req.Email)
 	if err != nil {
 		return nil, ErrInvalidCredentials
 	}
 
-	if user.Status == db.UserStatusBANNED {
-		return nil, ErrUserBanned
+	// Accounts created through a provider have no password to sign in with
+	if !user.PasswordHash.Valid {
+		return nil, ErrInvalidCredentials
 	}
-
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash.String), []byte(req.Password)); err != nil {
 		return nil, ErrInvalidCredentials
 	}
 
-	return s.issueTokens(ctx, user)
+	// Checked after the password so account state is only revealed to its owner
+	if err := s.checkSignInAllowed(user); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user, client)
 }
 
-func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*dto.AuthResponse, error) {
-	session, err := s.queries.GetSessionByRefreshToken(ctx, pgtype.Text{String: refreshToken, Valid: true})
-	if err != nil || time.Now().After(session.ExpiresAt.Time) {
+// Refresh rotates the refresh token inside its session, which is the token's
+// family. The old token is remembered as rotated; presenting it again means
+// two parties hold tokens of this family, so the whole session is revoked.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string, client dto.ClientInfo) (*dto.AuthResponse, error) {
+	hash := hashToken(refreshToken)
+	session, err := s.queries.GetSessionByRefreshToken(ctx, pgtype.Text{String: hash, Valid: true})
+	if errors.Is(err, pgx.ErrNoRows) {
+		s.detectReuse(ctx, hash, client)
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(session.ExpiresAt.Time) {
 		return nil, ErrInvalidToken
 	}
 
@@ -84,35 +138,229 @@ func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*dto.Au
 	if err != nil {
 		return nil, err
 	}
+	if err := s.checkSignInAllowed(user); err != nil {
+		return nil, err
+	}
 
-	// Token Rotation: Delete old session and issue new one
-	if err := s.queries.DeleteSession(ctx, session.ID); err != nil {
-		log.Printf("failed to delete old session %s: %v", session.ID, err)
+	access, err := s.jwt.Generate(user.ID, string(user.Role), session.ID, token.AccessTokenDuration)
+	if err != nil {
+		return nil, err
 	}
+	refresh := uuid.New().String()
 
-	return s.issueTokens(ctx, user)
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.queries.WithTx(tx)
+
+	n, err := qtx.RotateSession(ctx, db.RotateSessionParams{
+		NewRefreshToken: pgtype.Text{String: hashToken(refresh), Valid: true},
+		ExpiresAt:       pgtype.Timestamptz{Time: time.Now().Add(token.RefreshTokenDuration), Valid: true},
+		UserAgent:       optText(client.UserAgent),
+		IpAddress:       optText(client.IPAddress),
+		ID:              session.ID,
+		OldRefreshToken: session.RefreshToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		// A concurrent refresh rotated this token first
+		return nil, ErrInvalidToken
+	}
+	if err := qtx.CreateRotatedRefreshToken(ctx, db.CreateRotatedRefreshTokenParams{TokenHash: hash, SessionID: session.ID}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &dto.AuthResponse{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		User:         mapper.ToUserResponse(user),
+	}, nil
+}
+
+// detectReuse revokes the family of a rotated-out refresh token. A token
+// rotated within refreshReuseGrace is let off, since two tabs refreshing at
+// the same moment look exactly like that.
+func (s *AuthService) detectReuse(ctx context.Context, hash string, client dto.ClientInfo) {
+	rotated, err := s.queries.GetRotatedRefreshToken(ctx, hash)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("refresh token reuse lookup: %v", err)
+		}
+		return
+	}
+	if time.Since(rotated.RotatedAt.Time) < refreshReuseGrace {
+		return
+	}
+
+	if err := s.queries.DeleteSession(ctx, rotated.SessionID); err != nil {
+		log.Printf("revoke session %s after refresh token reuse: %v", rotated.SessionID, err)
+		return
+	}
+	recordSecurityEvent(ctx, s.queries, rotated.UserID, dto.SecurityEventRefreshTokenReuse, rotated.SessionID, client, map[string]any{
+		"rotated_at": rotated.RotatedAt.Time,
+	})
 }
 
 func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
-	return s.queries.DeleteSessionByRefreshToken(ctx, pgtype.Text{String: refreshToken, Valid: true})
+	return s.queries.DeleteSessionByRefreshToken(ctx, pgtype.Text{String: hashToken(refreshToken), Valid: true})
 }
 
 func (s *AuthService) GetUser(ctx context.Context, userID int32) (db.User, error) {
 	return s.queries.GetUserByID(ctx, userID)
 }
 
-// Helper to bundle token issuance
-func (s *AuthService) issueTokens(ctx context.Context, user db.User) (*dto.AuthResponse, error) {
-	access, err := s.jwt.Generate(user.ID, string(user.Role), token.AccessTokenDuration)
+// VerifyEmail confirms the address a verification token was issued for and
+// activates a PENDING account. Using a token again is harmless; a token issued
+// before the email changed no longer matches.
+func (s *AuthService) VerifyEmail(ctx context.Context, verifyToken string) (db.User, error) {
+	userID, email, err := s.jwt.VerifyPurpose(token.PurposeVerifyEmail, verifyToken)
+	if err != nil {
+		return db.User{}, ErrInvalidVerifyToken
+	}
+
+	return s.confirmEmail(ctx, userID, email)
+}
+
+// confirmEmail marks an address verified and activates a PENDING account,
+// recording the reason in the status log
+func (s *AuthService) confirmEmail(ctx context.Context, userID int32, email string) (db.User, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return db.User{}, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.queries.WithTx(tx)
+
+	if err := qtx.SetStatusChangeReason(ctx, "email verified"); err != nil {
+		return db.User{}, err
+	}
+	user, err := qtx.VerifyUserEmail(ctx, db.VerifyUserEmailParams{ID: userID, Email: email})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.User{}, ErrInvalidVerifyToken
+		}
+		return db.User{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return db.User{}, err
+	}
+	return user, nil
+}
+
+// ResendVerification mails a new link to an unverified account. It reports
+// nothing back, so the endpoint cannot be used to probe which emails are
+// registered; unknown, verified and rate-limited addresses are silently skipped.
+func (s *AuthService) ResendVerification(ctx context.Context, email string) error {
+	user, err := s.queries.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	s.sendVerification(ctx, user)
+	return nil
+}
+
+// sendVerification mails a verification link unless the account is verified or
+// was sent one within the cooldown
+func (s *AuthService) sendVerification(ctx context.Context, user db.User) {
+	if user.IsVerified {
+		return
+	}
+
+	n, err := s.queries.ClaimVerificationSend(ctx, db.ClaimVerificationSendParams{
+		ID:              user.ID,
+		CooldownSeconds: int32(verificationResendCooldown / time.Second),
+	})
+	if err != nil {
+		log.Printf("claim verification send for user %d: %v", user.ID, err)
+		return
+	}
+	if n == 0 {
+		return
+	}
+
+	verifyToken, err := s.jwt.GeneratePurpose(token.PurposeVerifyEmail, user.ID, user.Email, token.EmailVerificationDuration)
+	if err != nil {
+		log.Printf("verification token for user %d: %v", user.ID, err)
+		return
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your Filmophilia email address",
+		Body: "Hi " + user.Username + ",\n\n" +
+			"Confirm your email address to activate your account:\n\n" +
+			s.config.VerifyURL + "?token=" + url.QueryEscape(verifyToken) + "\n\n" +
+			"The link expires in 24 hours. If you did not sign up, you can ignore this email.\n",
+	}
+
+	s.deliver(ctx, msg)
+}
+
+// deliver hands a message to the mailer in the background so a slow mail
+// server neither delays the response nor shows in its timing
+func (s *AuthService) deliver(ctx context.Context, msg mailer.Message) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), mailSendTimeout)
+		defer cancel()
+		if err := s.mailer.Send(ctx, msg); err != nil {
+			log.Printf("mail %q to %s: %v", msg.Subject, msg.To, err)
+		}
+	}()
+}
+
+// checkSignInAllowed refuses new tokens to moderated accounts and, unless the
+// deployment allows it, to accounts that have not verified their email
+func (s *AuthService) checkSignInAllowed(user db.User) error {
+	switch user.Status {
+	case db.UserStatusBANNED:
+		return ErrUserBanned
+	case db.UserStatusSUSPENDED:
+		return ErrUserSuspended
+	}
+	if !user.IsVerified && !s.config.AllowUnverifiedLogin {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+// getUserByUsername resolves a public username, translating a missing row to ErrUserNotFound
+func getUserByUsername(ctx context.Context, q *db.Queries, username string) (db.User, error) {
+	user, err := q.GetUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.User{}, ErrUserNotFound
+		}
+		return db.User{}, err
+	}
+	return user, nil
+}
+
+// Helper to bundle token issuance: opens a new session for the client
+func (s *AuthService) issueTokens(ctx context.Context, user db.User, client dto.ClientInfo) (*dto.AuthResponse, error) {
+	sessionID := uuid.New().String()
+	access, err := s.jwt.Generate(user.ID, string(user.Role), sessionID, token.AccessTokenDuration)
 	if err != nil {
 		return nil, err
 	}
 
 	refresh := uuid.New().String()
 	_, err = s.queries.CreateSession(ctx, db.CreateSessionParams{
-		ID:           uuid.New().String(),
+		ID:           sessionID,
 		UserID:       user.ID,
-		RefreshToken: pgtype.Text{String: refresh, Valid: true},
+		RefreshToken: pgtype.Text{String: hashToken(refresh), Valid: true},
+		UserAgent:    optText(client.UserAgent),
+		IpAddress:    optText(client.IPAddress),
 		ExpiresAt:    pgtype.Timestamptz{Time: time.Now().Add(token.RefreshTokenDuration), Valid: true},
 	})
 
@@ -122,3 +370,10 @@ func (s *AuthService) issueTokens(ctx context.Context, user db.User) (*dto.AuthR
 		User:         mapper.ToUserResponse(user),
 	}, err
 }
+
+// hashToken is how refresh and reset tokens are stored: they are random, so an
+// unsalted SHA-256 is enough to make a leaked table useless
+func hashToken(t string) string {
+	sum := sha256.Sum256([]byte(t))
+	return hex.EncodeToString(sum[:])
+}
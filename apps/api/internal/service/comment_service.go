@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/mapper"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrCommentNotFound = errors.New("comment not found")
+	ErrNotCommentOwner = errors.New("you can only modify your own comments")
+	ErrInvalidParent   = errors.New("parent comment does not belong to this movie or was deleted")
+)
+
+type CommentService struct {
+	queries       *db.Queries
+	notifications *NotificationService
+}
+
+func NewCommentService(q *db.Queries, ns *NotificationService) *CommentService {
+	return &CommentService{queries: q, notifications: ns}
+}
+
+func (s *CommentService) Create(ctx context.Context, userID int32, slug string, req dto.CreateCommentRequest) (*dto.CommentResponse, error) {
+	movie, err := getMovieBySlug(ctx, s.queries, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		parent   db.Comment
+		parentID pgtype.Int4
+	)
+	if req.ParentID != nil {
+		parent, err = s.queries.GetCommentByID(ctx, *req.ParentID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrInvalidParent
+			}
+			return nil, err
+		}
+		if parent.MovieID != movie.ID || parent.DeletedAt.Valid {
+			return nil, ErrInvalidParent
+		}
+		parentID = pgtype.Int4{Int32: parent.ID, Valid: true}
+	}
+
+	comment, err := s.queries.CreateComment(ctx, db.CreateCommentParams{
+		UserID:   userID,
+		MovieID:  movie.ID,
+		ParentID: parentID,
+		Content:  req.Content,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		s.notifications.NotifyReply(ctx, comment, parent, movie.Slug)
+	}
+
+	return s.getOne(ctx, comment.ID, userID)
+}
+
+func (s *CommentService) Update(ctx context.Context, userID, id int32, content string) (*dto.CommentResponse, error) {
+	if _, err := s.getOwnedComment(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.queries.UpdateCommentContent(ctx, db.UpdateCommentContentParams{ID: id, Content: content}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+
+	return s.getOne(ctx, id, userID)
+}
+
+// Delete soft-deletes a comment so its replies keep their place in the thread
+func (s *CommentService) Delete(ctx context.Context, userID, id int32) error {
+	if _, err := s.getOwnedComment(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.queries.SoftDeleteComment(ctx, id)
+}
+
+// ListByMovie returns a cursor page of top-level threads with replies down to q.Depth.
+// viewerID personalises reactions and is 0 for anonymous callers.
+func (s *CommentService) ListByMovie(ctx context.Context, slug string, q dto.CommentTreeQuery, viewerID int32) (dto.CursorPage[dto.CommentResponse], error) {
+	movie, err := getMovieBySlug(ctx, s.queries, slug)
+	if err != nil {
+		return dto.CursorPage[dto.CommentResponse]{}, err
+	}
+
+	cursorAt, cursorID, err := decodeCursor(q.Cursor)
+	if err != nil {
+		return dto.CursorPage[dto.CommentResponse]{}, err
+	}
+
+	// Fetch one extra root to learn whether another page exists
+	roots, err := s.queries.ListTopLevelComments(ctx, db.ListTopLevelCommentsParams{
+		MovieID:         movie.ID,
+		CursorCreatedAt: cursorAt,
+		CursorID:        cursorID,
+		Limit:           q.Limit + 1,
+	})
+	if err != nil {
+		return dto.CursorPage[dto.CommentResponse]{}, err
+	}
+
+	var next string
+	if len(roots) > int(q.Limit) {
+		roots = roots[:q.Limit]
+		last := roots[len(roots)-1]
+		next = encodeCursor(last.CreatedAt.Time, last.ID)
+	}
+
+	rootIDs := make([]int32, 0, len(roots))
+	for _, r := range roots {
+		rootIDs = append(rootIDs, r.ID)
+	}
+
+	threads, err := s.loadTrees(ctx, rootIDs, q.Depth, viewerID)
+	if err != nil {
+		return dto.CursorPage[dto.CommentResponse]{}, err
+	}
+	return dto.NewCursorPage(threads, next), nil
+}
+
+// Thread returns a single comment with its replies down to depth, for "continue thread" links
+func (s *CommentService) Thread(ctx context.Context, id, depth, viewerID int32) (*dto.CommentResponse, error) {
+	threads, err := s.loadTrees(ctx, []int32{id}, depth, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(threads) == 0 {
+		return nil, ErrCommentNotFound
+	}
+	return &threads[0], nil
+}
+
+func (s *CommentService) loadTrees(ctx context.Context, rootIDs []int32, depth, viewerID int32) ([]dto.CommentResponse, error) {
+	if len(rootIDs) == 0 {
+		return []dto.CommentResponse{}, nil
+	}
+	rows, err := s.queries.ListCommentTree(ctx, db.ListCommentTreeParams{RootIds: rootIDs, MaxDepth: depth})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int32, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.ID)
+	}
+	reactions, err := commentReactionSummaries(ctx, s.queries, ids, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.BuildCommentTree(rows, rootIDs, depth, reactions), nil
+}
+
+func (s *CommentService) getOne(ctx context.Context, id, viewerID int32) (*dto.CommentResponse, error) {
+	return s.Thread(ctx, id, 0, viewerID)
+}
+
+// getOwnedComment loads a live comment and checks that userID wrote it
+func (s *CommentService) getOwnedComment(ctx context.Context, userID, id int32) (db.Comment, error) {
+	comment, err := s.queries.GetCommentByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Comment{}, ErrCommentNotFound
+		}
+		return db.Comment{}, err
+	}
+	if comment.DeletedAt.Valid {
+		return db.Comment{}, ErrCommentNotFound
+	}
+	if comment.UserID != userID {
+		return db.Comment{}, ErrNotCommentOwner
+	}
+	return comment, nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Keyset cursors are an opaque encoding of the (created_at, id) of the last row served
+
+func encodeCursor(createdAt time.Time, id int32) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatInt(int64(id), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor returns NULL values for an empty cursor so queries start from the top
+func decodeCursor(cursor string) (pgtype.Timestamptz, pgtype.Int4, error) {
+	if cursor == "" {
+		return pgtype.Timestamptz{}, pgtype.Int4{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return pgtype.Timestamptz{}, pgtype.Int4{}, ErrInvalidCursor
+	}
+
+	ts, idStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return pgtype.Timestamptz{}, pgtype.Int4{}, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return pgtype.Timestamptz{}, pgtype.Int4{}, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil || id <= 0 {
+		return pgtype.Timestamptz{}, pgtype.Int4{}, ErrInvalidCursor
+	}
+
+	return pgtype.Timestamptz{Time: t, Valid: true}, pgtype.Int4{Int32: int32(id), Valid: true}, nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		createdAt time.Time
+		id        int32
+	}{
+		{"utc", time.Date(2026, 10, 17, 12, 30, 0, 0, time.UTC), 1},
+		{"nanoseconds survive", time.Date(2026, 1, 2, 3, 4, 5, 123456789, time.UTC), 42},
+		{"other zones are normalised", time.Date(2026, 5, 6, 7, 8, 9, 0, time.FixedZone("IRST", 3*3600+1800)), 7},
+		{"largest id", time.Unix(0, 0), 1<<31 - 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, id, err := decodeCursor(encodeCursor(tt.createdAt, tt.id))
+			if err != nil {
+				t.Fatalf("decodeCursor: %v", err)
+			}
+			if !ts.Valid || !ts.Time.Equal(tt.createdAt) {
+				t.Errorf("created_at = %v, want %v", ts.Time, tt.createdAt)
+			}
+			if !id.Valid || id.Int32 != tt.id {
+				t.Errorf("id = %v, want %d", id.Int32, tt.id)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorEmpty(t *testing.T) {
+	ts, id, err := decodeCursor("")
+	if err != nil {
+		t.Fatalf("decodeCursor: %v", err)
+	}
+	if ts.Valid || id.Valid {
+		t.Errorf("got (%v, %v), want NULLs to start from the top", ts, id)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "!!!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte("2026-10-17T12:30:00Z|1"))},
+		{"no separator", encode("2026-10-17T12:30:00Z")},
+		{"bad time", encode("yesterday|1")},
+		{"bad id", encode("2026-10-17T12:30:00Z|one")},
+		{"zero id", encode("2026-10-17T12:30:00Z|0")},
+		{"negative id", encode("2026-10-17T12:30:00Z|-5")},
+		{"id overflows int32", encode("2026-10-17T12:30:00Z|2147483648")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := decodeCursor(tt.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decodeCursor(%q) error = %v, want ErrInvalidCursor", tt.cursor, err)
+			}
+		})
+	}
+}
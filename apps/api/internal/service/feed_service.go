@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/mapper"
+)
+
+const (
+	// Consecutive activities of one user with the same action collapse into a
+	// single entry while each is within this long of the previous one
+	feedBurstWindow = time.Hour
+
+	// Raw activities scanned per requested entry, bounded so a page stays one cheap query
+	feedScanFactor = 5
+	feedMaxScan    = 250
+
+	// Items kept in a collapsed entry; Count still reports the whole burst
+	feedGroupSample = 10
+)
+
+// collapsible actions are the ones that happen in bursts; reviews always stand alone
+var collapsibleActions = map[string]bool{
+	dto.ActivityRated:       true,
+	dto.ActivityFollowed:    true,
+	dto.ActivityWatchlisted: true,
+}
+
+type FeedService struct {
+	queries *db.Queries
+}
+
+func NewFeedService(q *db.Queries) *FeedService {
+	return &FeedService{queries: q}
+}
+
+// feedGroup is an entry under construction plus the oldest activity it
+// consumed, which is where the next page resumes
+type feedGroup struct {
+	entry    dto.FeedEntryResponse
+	oldestAt time.Time
+	oldestID int32
+}
+
+// Feed returns a cursor page of timeline entries from the users viewerID follows.
+// q.Limit counts entries, not activities, so a page scans several times as many
+// activities and never ends in the middle of a burst unless the burst alone
+// fills the scan.
+func (s *FeedService) Feed(ctx context.Context, viewerID int32, q dto.CursorQuery) (dto.CursorPage[dto.FeedEntryResponse], error) {
+	cursorAt, cursorID, err := decodeCursor(q.Cursor)
+	if err != nil {
+		return dto.CursorPage[dto.FeedEntryResponse]{}, err
+	}
+
+	scan := min(q.Limit*feedScanFactor, feedMaxScan)
+	rows, err := s.queries.ListFeedActivities(ctx, db.ListFeedActivitiesParams{
+		ViewerID:        viewerID,
+		CursorCreatedAt: cursorAt,
+		CursorID:        cursorID,
+		Limit:           scan,
+	})
+	if err != nil {
+		return dto.CursorPage[dto.FeedEntryResponse]{}, err
+	}
+
+	hasMore := len(rows) == int(scan)
+	groups := collapseActivities(rows)
+
+	// The oldest group may continue in rows past the scan, so it is left for
+	// the next page unless it is all this page has
+	if hasMore && len(groups) > 1 {
+		groups = groups[:len(groups)-1]
+	}
+	if len(groups) > int(q.Limit) {
+		groups = groups[:q.Limit]
+		hasMore = true
+	}
+
+	var next string
+	switch {
+	case hasMore && len(groups) > 0:
+		last := groups[len(groups)-1]
+		next = encodeCursor(last.oldestAt, last.oldestID)
+	case hasMore:
+		// Every scanned activity pointed at deleted entities; skip past them
+		last := rows[len(rows)-1]
+		next = encodeCursor(last.CreatedAt.Time, last.ID)
+	}
+
+	entries := make([]dto.FeedEntryResponse, 0, len(groups))
+	for _, g := range groups {
+		entries = append(entries, g.entry)
+	}
+	return dto.NewCursorPage(entries, next), nil
+}
+
+// collapseActivities folds newest-first activities into timeline entries.
+// Only adjacent activities are merged, which keeps the entries in strict
+// time order and lets a page boundary be a single (created_at, id) cursor.
+func collapseActivities(rows []db.ListFeedActivitiesRow) []feedGroup {
+	var groups []feedGroup
+	for _, r := range rows {
+		item, ok := mapper.ToFeedItemResponse(r)
+		if !ok {
+			continue
+		}
+		at := r.CreatedAt.Time
+
+		if n := len(groups); n > 0 {
+			g := &groups[n-1]
+			if collapsibleActions[r.Action] &&
+				g.entry.Action == r.Action &&
+				g.entry.Actor.ID == r.UserID &&
+				g.oldestAt.Sub(at) <= feedBurstWindow {
+				g.entry.Count++
+				if len(g.entry.Items) < feedGroupSample {
+					g.entry.Items = append(g.entry.Items, item)
+				}
+				g.oldestAt, g.oldestID = at, r.ID
+				continue
+			}
+		}
+
+		groups = append(groups, feedGroup{
+			entry: dto.FeedEntryResponse{
+				Action:    r.Action,
+				Actor:     mapper.ToFeedActor(r),
+				Count:     1,
+				Items:     []dto.FeedItemResponse{item},
+				CreatedAt: at,
+			},
+			oldestAt: at,
+			oldestID: r.ID,
+		})
+	}
+	return groups
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var feedNow = time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+// activity builds a feed row about a live entity, minutesAgo before feedNow
+func activity(id, userID int32, action string, minutesAgo int) db.ListFeedActivitiesRow {
+	r := db.ListFeedActivitiesRow{
+		ID:        id,
+		UserID:    userID,
+		Action:    action,
+		EntityID:  1000 + id,
+		CreatedAt: pgtype.Timestamptz{Time: feedNow.Add(-time.Duration(minutesAgo) * time.Minute), Valid: true},
+		Username:  fmt.Sprintf("user%d", userID),
+	}
+	switch action {
+	case dto.ActivityFollowed:
+		r.EntityType = db.EntityTypeUSER
+		r.TargetUsername = pgtype.Text{String: fmt.Sprintf("target%d", id), Valid: true}
+	case dto.ActivityReviewed:
+		r.EntityType = db.EntityTypeREVIEW
+		r.MovieID = pgtype.Int4{Int32: 1000 + id, Valid: true}
+		r.ReviewID = pgtype.Int4{Int32: id, Valid: true}
+	default:
+		r.EntityType = db.EntityTypeMOVIE
+		r.MovieID = pgtype.Int4{Int32: 1000 + id, Valid: true}
+	}
+	return r
+}
+
+// deleted strips the joined entity, as when it was deleted after the activity
+func deleted(r db.ListFeedActivitiesRow) db.ListFeedActivitiesRow {
+	r.MovieID = pgtype.Int4{}
+	r.TargetUsername = pgtype.Text{}
+	return r
+}
+
+// groupShape renders a group as "rated by 1: 3,2,1 (3)", listing the sampled
+// activity ids and the burst size
+func groupShape(g feedGroup) string {
+	ids := make([]string, len(g.entry.Items))
+	for i, item := range g.entry.Items {
+		ids[i] = fmt.Sprint(item.ActivityID)
+	}
+	return fmt.Sprintf("%s by %d: %s (%d)", g.entry.Action, g.entry.Actor.ID, strings.Join(ids, ","), g.entry.Count)
+}
+
+func TestCollapseActivities(t *testing.T) {
+	rated, reviewed, followed := dto.ActivityRated, dto.ActivityReviewed, dto.ActivityFollowed
+
+	tests := []struct {
+		name string
+		rows []db.ListFeedActivitiesRow
+		want []string
+	}{
+		{
+			name: "empty",
+			want: nil,
+		},
+		{
+			name: "a burst collapses into one entry",
+			rows: []db.ListFeedActivitiesRow{
+				activity(3, 1, rated, 0),
+				activity(2, 1, rated, 10),
+				activity(1, 1, rated, 20),
+			},
+			want: []string{"rated by 1: 3,2,1 (3)"},
+		},
+		{
+			name: "reviews always stand alone",
+			rows: []db.ListFeedActivitiesRow{
+				activity(2, 1, reviewed, 0),
+				activity(1, 1, reviewed, 5),
+			},
+			want: []string{"reviewed by 1: 2 (1)", "reviewed by 1: 1 (1)"},
+		},
+		{
+			name: "another actor or action ends the burst",
+			rows: []db.ListFeedActivitiesRow{
+				activity(4, 1, rated, 0),
+				activity(3, 2, rated, 1),
+				activity(2, 2, followed, 2),
+				activity(1, 2, followed, 3),
+			},
+			want: []string{"rated by 1: 4 (1)", "rated by 2: 3 (1)", "followed by 2: 2,1 (2)"},
+		},
+		{
+			name: "only adjacent activities merge",
+			rows: []db.ListFeedActivitiesRow{
+				activity(3, 1, rated, 0),
+				activity(2, 2, rated, 1),
+				activity(1, 1, rated, 2),
+			},
+			want: []string{"rated by 1: 3 (1)", "rated by 2: 2 (1)", "rated by 1: 1 (1)"},
+		},
+		{
+			name: "the window is measured from the previous activity",
+			rows: []db.ListFeedActivitiesRow{
+				activity(4, 1, rated, 0),
+				activity(3, 1, rated, 50),
+				activity(2, 1, rated, 110),
+				activity(1, 1, rated, 171),
+			},
+			want: []string{"rated by 1: 4,3,2 (3)", "rated by 1: 1 (1)"},
+		},
+		{
+			name: "activities about deleted entities are skipped",
+			rows: []db.ListFeedActivitiesRow{
+				activity(3, 1, rated, 0),
+				deleted(activity(2, 1, rated, 1)),
+				activity(1, 1, rated, 2),
+				deleted(activity(0, 2, followed, 3)),
+			},
+			want: []string{"rated by 1: 3,1 (2)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups := collapseActivities(tt.rows)
+			var got []string
+			for _, g := range groups {
+				got = append(got, groupShape(g))
+			}
+			if strings.Join(got, "; ") != strings.Join(tt.want, "; ") {
+				t.Errorf("groups = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollapseActivitiesSampleAndCursor(t *testing.T) {
+	var rows []db.ListFeedActivitiesRow
+	for i := range feedGroupSample + 5 {
+		rows = append(rows, activity(int32(100-i), 1, dto.ActivityWatchlisted, i))
+	}
+
+	groups := collapseActivities(rows)
+	if len(groups) != 1 {
+		t.Fatalf("got %d groups, want 1", len(groups))
+	}
+	g := groups[0]
+	if g.entry.Count != len(rows) {
+		t.Errorf("count = %d, want %d", g.entry.Count, len(rows))
+	}
+	if len(g.entry.Items) != feedGroupSample {
+		t.Errorf("sampled %d items, want %d", len(g.entry.Items), feedGroupSample)
+	}
+
+	// The entry is dated by its newest activity and resumes after its oldest
+	last := rows[len(rows)-1]
+	if !g.entry.CreatedAt.Equal(feedNow) {
+		t.Errorf("created_at = %v, want %v", g.entry.CreatedAt, feedNow)
+	}
+	if g.oldestID != last.ID || !g.oldestAt.Equal(last.CreatedAt.Time) {
+		t.Errorf("oldest = (%v, %d), want (%v, %d)", g.oldestAt, g.oldestID, last.CreatedAt.Time, last.ID)
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/mapper"
+)
+
+var ErrCannotFollowSelf = errors.New("you cannot follow yourself")
+
+type FollowService struct {
+	queries       *db.Queries
+	notifications *NotificationService
+}
+
+func NewFollowService(q *db.Queries, ns *NotificationService) *FollowService {
+	return &FollowService{queries: q, notifications: ns}
+}
+
+// Follow makes followerID follow username. Following twice is a no-op and
+// only the first follow notifies the followed user.
+func (s *FollowService) Follow(ctx context.Context, followerID int32, username string) (*dto.FollowStatusResponse, error) {
+	target, err := getUserByUsername(ctx, s.queries, username)
+	if err != nil {
+		return nil, err
+	}
+	if target.ID == followerID {
+		return nil, ErrCannotFollowSelf
+	}
+
+	n, err := s.queries.CreateFollow(ctx, db.CreateFollowParams{FollowerID: followerID, FollowingID: target.ID})
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		recordActivity(ctx, s.queries, followerID, dto.ActivityFollowed, db.EntityTypeUSER, target.ID, nil)
+		s.notifications.NotifyNewFollower(ctx, followerID, target.ID)
+	}
+
+	return s.status(ctx, target.ID, true)
+}
+
+func (s *FollowService) Unfollow(ctx context.Context, followerID int32, username string) (*dto.FollowStatusResponse, error) {
+	target, err := getUserByUsername(ctx, s.queries, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.queries.DeleteFollow(ctx, db.DeleteFollowParams{FollowerID: followerID, FollowingID: target.ID}); err != nil {
+		return nil, err
+	}
+
+	return s.status(ctx, target.ID, false)
+}
+
+func (s *FollowService) ListFollowers(ctx context.Context, username string, q dto.PaginationQuery, viewerID int32) (dto.PaginatedResponse[dto.FollowUserResponse], error) {
+	user, err := getVisibleUser(ctx, s.queries, username, viewerID)
+	if err != nil {
+		return dto.PaginatedResponse[dto.FollowUserResponse]{}, err
+	}
+
+	total, err := s.queries.CountFollowers(ctx, user.ID)
+	if err != nil {
+		return dto.PaginatedResponse[dto.FollowUserResponse]{}, err
+	}
+
+	rows, err := s.queries.ListFollowers(ctx, db.ListFollowersParams{FollowingID: user.ID, Limit: q.Limit(), Offset: q.Offset()})
+	if err != nil {
+		return dto.PaginatedResponse[dto.FollowUserResponse]{}, err
+	}
+
+	return dto.NewPaginatedResponse(mapper.ToFollowUserResponses(rows), q, total), nil
+}
+
+func (s *FollowService) ListFollowing(ctx context.Context, username string, q dto.PaginationQuery, viewerID int32) (dto.PaginatedResponse[dto.FollowUserResponse], error) {
+	user, err := getVisibleUser(ctx, s.queries, username, viewerID)
+	if err != nil {
+		return dto.PaginatedResponse[dto.FollowUserResponse]{}, err
+	}
+
+	total, err := s.queries.CountFollowing(ctx, user.ID)
+	if err != nil {
+		return dto.PaginatedResponse[dto.FollowUserResponse]{}, err
+	}
+
+	rows, err := s.queries.ListFollowing(ctx, db.ListFollowingParams{FollowerID: user.ID, Limit: q.Limit(), Offset: q.Offset()})
+	if err != nil {
+		return dto.PaginatedResponse[dto.FollowUserResponse]{}, err
+	}
+
+	converted := make([]db.ListFollowersRow, 0, len(rows))
+	for _, r := range rows {
+		converted = append(converted, db.ListFollowersRow(r))
+	}
+	return dto.NewPaginatedResponse(mapper.ToFollowUserResponses(converted), q, total), nil
+}
+
+// ListMutuals returns the users that username follows and who follow them back
+func (s *FollowService) ListMutuals(ctx context.Context, username string, q dto.PaginationQuery, viewerID int32) (dto.PaginatedResponse[dto.FollowUserResponse], error) {
+	user, err := getVisibleUser(ctx, s.queries, username, viewerID)
+	if err != nil {
+		return dto.PaginatedResponse[dto.FollowUserResponse]{}, err
+	}
+
+	total, err := s.queries.CountMutualFollows(ctx, user.ID)
+	if err != nil {
+		return dto.PaginatedResponse[dto.FollowUserResponse]{}, err
+	}
+
+	rows, err := s.queries.ListMutualFollows(ctx, db.ListMutualFollowsParams{FollowerID: user.ID, Limit: q.Limit(), Offset: q.Offset()})
+	if err != nil {
+		return dto.PaginatedResponse[dto.FollowUserResponse]{}, err
+	}
+
+	converted := make([]db.ListFollowersRow, 0, len(rows))
+	for _, r := range rows {
+		converted = append(converted, db.ListFollowersRow(r))
+	}
+	return dto.NewPaginatedResponse(mapper.ToFollowUserResponses(converted), q, total), nil
+}
+
+func (s *FollowService) status(ctx context.Context, targetID int32, following bool) (*dto.FollowStatusResponse, error) {
+	followers, err := s.queries.CountFollowers(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.FollowStatusResponse{Following: following, FollowerCount: followers}, nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/mapper"
+	"github.com/jackc/pgx/v5"
+)
+
+// Number of credits shown on the movie page per department
+const (
+	maxDirectors      = 5
+	topBilledCastSize = 10
+)
+
+var (
+	ErrMovieNotFound = errors.New("movie not found")
+	ErrInvalidFilter = errors.New("invalid filter range")
+)
+
+type MovieService struct {
+	queries *db.Queries
+}
+
+func NewMovieService(q *db.Queries) *MovieService {
+	return &MovieService{queries: q}
+}
+
+func (s *MovieService) ListMovies(ctx context.Context, f dto.MovieFilterQuery) (dto.PaginatedResponse[dto.MovieSummaryResponse], error) {
+	if (f.YearFrom != nil && f.YearTo != nil && *f.YearFrom > *f.YearTo) ||
+		(f.RuntimeMin != nil && f.RuntimeMax != nil && *f.RuntimeMin > *f.RuntimeMax) {
+		return dto.PaginatedResponse[dto.MovieSummaryResponse]{}, ErrInvalidFilter
+	}
+
+	filter := db.CountMoviesParams{
+		Genre:      optText(f.Genre),
+		YearFrom:   optInt4(f.YearFrom),
+		YearTo:     optInt4(f.YearTo),
+		RuntimeMin: optInt4(f.RuntimeMin),
+		RuntimeMax: optInt4(f.RuntimeMax),
+		Language:   optText(f.Language),
+		Country:    optText(f.Country),
+	}
+
+	total, err := s.queries.CountMovies(ctx, filter)
+	if err != nil {
+		return dto.PaginatedResponse[dto.MovieSummaryResponse]{}, err
+	}
+
+	movies, err := s.queries.ListMovies(ctx, db.ListMoviesParams{
+		Genre:      filter.Genre,
+		YearFrom:   filter.YearFrom,
+		YearTo:     filter.YearTo,
+		RuntimeMin: filter.RuntimeMin,
+		RuntimeMax: filter.RuntimeMax,
+		Language:   filter.Language,
+		Country:    filter.Country,
+		Limit:      f.Limit(),
+		Offset:     f.Offset(),
+	})
+	if err != nil {
+		return dto.PaginatedResponse[dto.MovieSummaryResponse]{}, err
+	}
+
+	return dto.NewPaginatedResponse(mapper.ToMovieSummaryResponses(movies), f.PaginationQuery, total), nil
+}
+
+func (s *MovieService) GetMovieBySlug(ctx context.Context, slug string) (*dto.MovieDetailResponse, error) {
+	movie, err := getMovieBySlug(ctx, s.queries, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	genres, err := s.queries.ListGenresByMovie(ctx, movie.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	directors, err := s.queries.ListMovieCredits(ctx, db.ListMovieCreditsParams{
+		MovieID:    movie.ID,
+		Department: db.DepartmentDIRECTING,
+		Limit:      maxDirectors,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cast, err := s.queries.ListMovieCredits(ctx, db.ListMovieCreditsParams{
+		MovieID:    movie.ID,
+		Department: db.DepartmentACTING,
+		Limit:      topBilledCastSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := mapper.ToMovieDetailResponse(movie, genres, directors, cast)
+	return &resp, nil
+}
+
+func (s *MovieService) ListGenres(ctx context.Context) ([]dto.GenreResponse, error) {
+	genres, err := s.queries.ListGenres(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ToGenreResponses(genres), nil
+}
+
+// getMovieBySlug resolves a public slug, translating a missing row to ErrMovieNotFound
+func getMovieBySlug(ctx context.Context, q *db.Queries, slug string) (db.Movie, error) {
+	movie, err := q.GetMovieBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Movie{}, ErrMovieNotFound
+		}
+		return db.Movie{}, err
+	}
+	return movie, nil
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/mapper"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// Channel the notifications trigger publishes on
+	notificationChannel = "notifications"
+
+	// Events buffered per stream; a client further behind than this misses
+	// notifications but still receives the next unread count
+	streamBuffer = 16
+
+	listenRetryDelay = 2 * time.Second
+)
+
+// notificationChange is the NOTIFY payload written by notify_notification_change()
+type notificationChange struct {
+	UserID         int32 `json:"user_id"`
+	NotificationID int32 `json:"notification_id"`
+}
+
+// Subscription is one open notification stream
+type Subscription struct {
+	userID int32
+	events chan dto.StreamEvent
+}
+
+// Events delivers stream events; it is closed when the hub shuts down
+func (s *Subscription) Events() <-chan dto.StreamEvent {
+	return s.events
+}
+
+// NotificationHub fans notification changes out to the SSE streams open on
+// this instance. Changes arrive through Postgres LISTEN/NOTIFY rather than
+// from NotificationService directly, so a notification written by any API
+// instance reaches a stream held by any other.
+type NotificationHub struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+
+	mu     sync.Mutex
+	subs   map[int32]map[*Subscription]struct{}
+	closed bool
+	cancel context.CancelFunc
+}
+
+func NewNotificationHub(pool *pgxpool.Pool, q *db.Queries) *NotificationHub {
+	return &NotificationHub{
+		pool:    pool,
+		queries: q,
+		subs:    make(map[int32]map[*Subscription]struct{}),
+	}
+}
+
+// Start runs the LISTEN loop in the background until Close
+func (h *NotificationHub) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.mu.Lock()
+	h.cancel = cancel
+	h.mu.Unlock()
+
+	go h.run(ctx)
+}
+
+// Close stops listening and ends every open stream. It is registered with the
+// HTTP server's shutdown so long-lived streams do not hold up the grace period.
+func (h *NotificationHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+	h.closed = true
+	if h.cancel != nil {
+		h.cancel()
+	}
+	for _, subs := range h.subs {
+		for sub := range subs {
+			close(sub.events)
+		}
+	}
+	h.subs = nil
+}
+
+// Subscribe opens a stream for a user. It returns false once the hub is closed.
+func (h *NotificationHub) Subscribe(userID int32) (*Subscription, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, false
+	}
+	sub := &Subscription{userID: userID, events: make(chan dto.StreamEvent, streamBuffer)}
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[*Subscription]struct{})
+	}
+	h.subs[userID][sub] = struct{}{}
+	return sub, true
+}
+
+func (h *NotificationHub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subs, ok := h.subs[sub.userID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	close(sub.events)
+	if len(subs) == 0 {
+		delete(h.subs, sub.userID)
+	}
+}
+
+func (h *NotificationHub) run(ctx context.Context) {
+	for {
+		err := h.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("notification listener: %v, retrying in %s", err, listenRetryDelay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+// listen holds a dedicated connection taken out of the pool, since a LISTENing
+// connection must not be handed to other queries
+func (h *NotificationHub) listen(ctx context.Context) error {
+	conn, err := h.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	pgConn := conn.Hijack()
+	defer pgConn.Close(context.Background())
+
+	if _, err := pgConn.Exec(ctx, "LISTEN "+notificationChannel); err != nil {
+		return err
+	}
+
+	for {
+		n, err := pgConn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var change notificationChange
+		if err := json.Unmarshal([]byte(n.Payload), &change); err != nil {
+			log.Printf("notification listener: bad payload %q: %v", n.Payload, err)
+			continue
+		}
+		h.dispatch(ctx, change)
+	}
+}
+
+// dispatch loads what changed only when this instance has a stream for the user
+func (h *NotificationHub) dispatch(ctx context.Context, change notificationChange) {
+	if !h.hasSubscribers(change.UserID) {
+		return
+	}
+
+	if change.NotificationID != 0 {
+		n, err := h.queries.GetNotification(ctx, change.NotificationID)
+		if err != nil {
+			log.Printf("notification listener: load notification %d: %v", change.NotificationID, err)
+		} else {
+			h.broadcast(change.UserID, dto.StreamEvent{Event: dto.StreamEventNotification, Data: mapper.ToNotificationResponse(n)})
+		}
+	}
+
+	unread, err := h.queries.CountUnreadNotifications(ctx, change.UserID)
+	if err != nil {
+		log.Printf("notification listener: unread count for user %d: %v", change.UserID, err)
+		return
+	}
+	h.broadcast(change.UserID, dto.StreamEvent{Event: dto.StreamEventUnreadCount, Data: dto.UnreadCountResponse{UnreadCount: unread}})
+}
+
+func (h *NotificationHub) hasSubscribers(userID int32) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs[userID]) > 0
+}
+
+// broadcast never blocks the listener: a stream with a full buffer skips the event
+func (h *NotificationHub) broadcast(userID int32, ev dto.StreamEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs[userID] {
+		select {
+		case sub.events <- ev:
+		default:
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/mapper"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// NotificationService is both the inbox behind /me/notifications and the single
+// place other services emit notifications from. Emitting is best effort: the
+// action that triggered it has already succeeded, so failures are only logged.
+type NotificationService struct {
+	queries *db.Queries
+}
+
+func NewNotificationService(q *db.Queries) *NotificationService {
+	return &NotificationService{queries: q}
+}
+
+// NotifyNewFollower tells a user someone followed them. Unfollowing and
+// following again does not notify twice.
+func (s *NotificationService) NotifyNewFollower(ctx context.Context, followerID, targetID int32) {
+	actor, ok := s.actor(ctx, followerID)
+	if !ok {
+		return
+	}
+	s.emitOnce(ctx, targetID, db.NotificationTypeNEWFOLLOWER, "New follower",
+		"@"+actor.Username+" started following you",
+		dto.NotificationMetadata{ActorID: actor.ID, ActorUsername: actor.Username})
+}
+
+// NotifyReviewReaction tells a review's author about a reaction; changing the
+// reaction type later does not notify again
+func (s *NotificationService) NotifyReviewReaction(ctx context.Context, actorID int32, review db.Review, reaction string) {
+	if actorID == review.UserID {
+		return
+	}
+	actor, ok := s.actor(ctx, actorID)
+	if !ok {
+		return
+	}
+	s.emitOnce(ctx, review.UserID, db.NotificationTypeNEWLIKE, "New reaction",
+		"@"+actor.Username+" reacted to your review",
+		dto.NotificationMetadata{ActorID: actor.ID, ActorUsername: actor.Username, ReviewID: review.ID, Reaction: reaction})
+}
+
+// NotifyCommentReaction tells a comment's author about a reaction
+func (s *NotificationService) NotifyCommentReaction(ctx context.Context, actorID int32, comment db.Comment, reaction string) {
+	if actorID == comment.UserID {
+		return
+	}
+	actor, ok := s.actor(ctx, actorID)
+	if !ok {
+		return
+	}
+	s.emitOnce(ctx, comment.UserID, db.NotificationTypeNEWLIKE, "New reaction",
+		"@"+actor.Username+" reacted to your comment",
+		dto.NotificationMetadata{ActorID: actor.ID, ActorUsername: actor.Username, CommentID: comment.ID, Reaction: reaction})
+}
+
+// NotifyReply tells the author of a parent comment about a reply
+func (s *NotificationService) NotifyReply(ctx context.Context, reply, parent db.Comment, movieSlug string) {
+	if reply.UserID == parent.UserID {
+		return
+	}
+	actor, ok := s.actor(ctx, reply.UserID)
+	if !ok {
+		return
+	}
+	s.emit(ctx, parent.UserID, db.NotificationTypeNEWCOMMENT, "New reply",
+		"@"+actor.Username+" replied to your comment",
+		dto.NotificationMetadata{ActorID: actor.ID, ActorUsername: actor.Username, MovieSlug: movieSlug, CommentID: reply.ID, ParentID: parent.ID})
+}
+
+// NotifyNewReview fans a new review out to all of the author's followers
+func (s *NotificationService) NotifyNewReview(ctx context.Context, review db.Review, movie db.Movie) {
+	actor, ok := s.actor(ctx, review.UserID)
+	if !ok {
+		return
+	}
+
+	meta, _ := json.Marshal(dto.NotificationMetadata{
+		ActorID:       actor.ID,
+		ActorUsername: actor.Username,
+		MovieSlug:     movie.Slug,
+		ReviewID:      review.ID,
+	})
+	if _, err := s.queries.CreateFollowerNotifications(ctx, db.CreateFollowerNotificationsParams{
+		Type:        db.NotificationTypeNEWREVIEW,
+		Title:       "New review",
+		Content:     optText("@" + actor.Username + " reviewed " + movie.Title),
+		Metadata:    meta,
+		FollowingID: actor.ID,
+	}); err != nil {
+		log.Printf("new review notifications for review %d: %v", review.ID, err)
+	}
+}
+
+// NotifyAccountStatus tells a user their account status changed. PENDING has
+// no notification type and is ignored.
+func (s *NotificationService) NotifyAccountStatus(ctx context.Context, userID int32, status db.UserStatus, reason string) {
+	var (
+		typ   db.NotificationType
+		title string
+	)
+	switch status {
+	case db.UserStatusACTIVE:
+		typ, title = db.NotificationTypeACCOUNTACTIVATED, "Your account is active"
+	case db.UserStatusSUSPENDED:
+		typ, title = db.NotificationTypeACCOUNTSUSPENDED, "Your account has been suspended"
+	case db.UserStatusBANNED:
+		typ, title = db.NotificationTypeACCOUNTBANNED, "Your account has been banned"
+	default:
+		return
+	}
+	s.emit(ctx, userID, typ, title, reason, dto.NotificationMetadata{Status: string(status), Reason: reason})
+}
+
+func (s *NotificationService) List(ctx context.Context, userID int32, q dto.NotificationsQuery) (*dto.NotificationListResponse, error) {
+	total, err := s.queries.CountNotifications(ctx, db.CountNotificationsParams{UserID: userID, UnreadOnly: q.Unread})
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.queries.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.queries.ListNotifications(ctx, db.ListNotificationsParams{
+		UserID:     userID,
+		UnreadOnly: q.Unread,
+		Limit:      q.Limit(),
+		Offset:     q.Offset(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.NotificationListResponse{
+		PaginatedResponse: dto.NewPaginatedResponse(mapper.ToNotificationResponses(rows), q.PaginationQuery, total),
+		UnreadCount:       unread,
+	}, nil
+}
+
+func (s *NotificationService) UnreadCount(ctx context.Context, userID int32) (int64, error) {
+	return s.queries.CountUnreadNotifications(ctx, userID)
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id int32) error {
+	n, err := s.queries.MarkNotificationRead(ctx, db.MarkNotificationReadParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllRead returns how many notifications were unread
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID int32) (int64, error) {
+	return s.queries.MarkAllNotificationsRead(ctx, userID)
+}
+
+func (s *NotificationService) Delete(ctx context.Context, userID, id int32) error {
+	n, err := s.queries.DeleteNotification(ctx, db.DeleteNotificationParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (s *NotificationService) actor(ctx context.Context, id int32) (db.User, bool) {
+	user, err := s.queries.GetUserByID(ctx, id)
+	if err != nil {
+		log.Printf("notification actor %d: %v", id, err)
+		return db.User{}, false
+	}
+	return user, true
+}
+
+func (s *NotificationService) emit(ctx context.Context, userID int32, typ db.NotificationType, title, content string, meta dto.NotificationMetadata) {
+	raw, _ := json.Marshal(meta)
+	if _, err := s.queries.CreateNotification(ctx, db.CreateNotificationParams{
+		UserID:   userID,
+		Type:     typ,
+		Title:    title,
+		Content:  optText(content),
+		Metadata: raw,
+	}); err != nil {
+		log.Printf("%s notification for user %d: %v", typ, userID, err)
+	}
+}
+
+// emitOnce skips the notification when the user already has one of the same
+// type about the same actor and target. The reaction type is left out of the
+// match so switching LIKE to LOVE does not notify again.
+func (s *NotificationService) emitOnce(ctx context.Context, userID int32, typ db.NotificationType, title, content string, meta dto.NotificationMetadata) {
+	key := meta
+	key.ActorUsername, key.Reaction = "", ""
+	raw, _ := json.Marshal(key)
+
+	exists, err := s.queries.HasNotification(ctx, db.HasNotificationParams{UserID: userID, Type: typ, Metadata: raw})
+	if err != nil {
+		log.Printf("%s notification lookup for user %d: %v", typ, userID, err)
+		return
+	}
+	if !exists {
+		s.emit(ctx, userID, typ, title, content, meta)
+	}
+}
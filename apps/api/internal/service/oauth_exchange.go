@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// How long the frontend has to redeem an exchange code
+	oauthExchangeDuration = time.Minute
+
+	returnToMaxLen = 2048
+)
+
+var (
+	ErrInvalidExchangeCode = errors.New("invalid or expired exchange code")
+	ErrInvalidReturnTo     = errors.New("return_to is not an allowed destination")
+)
+
+// OAuthConfig is where provider callbacks hand the browser back to the frontend
+type OAuthConfig struct {
+	// Frontend page that receives ?code=, ?linked= or ?error= after a callback
+	FrontendCallbackURL string
+	// Origins an absolute return_to may point at; paths are always allowed
+	ReturnToOrigins []string
+}
+
+// allowsReturnTo accepts a path on the frontend or an absolute http(s) URL on
+// a whitelisted origin. Anything else could turn sign-in into an open redirect.
+func (c OAuthConfig) allowsReturnTo(returnTo string) bool {
+	if returnTo == "" {
+		return true
+	}
+	if len(returnTo) > returnToMaxLen || strings.ContainsAny(returnTo, "\\\r\n\t") {
+		return false
+	}
+	// "//host" is a scheme-relative URL, not a path
+	if strings.HasPrefix(returnTo, "/") {
+		return !strings.HasPrefix(returnTo, "//")
+	}
+
+	u, err := url.Parse(returnTo)
+	if err != nil || u.User != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return slices.Contains(c.ReturnToOrigins, u.Scheme+"://"+u.Host)
+}
+
+// FrontendURL is the frontend callback page with params added to its query
+func (s *OAuthService) FrontendURL(params url.Values) string {
+	u, err := url.Parse(s.config.FrontendCallbackURL)
+	if err != nil {
+		return s.config.FrontendCallbackURL
+	}
+	q := u.Query()
+	for k, v := range params {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// Exchange redeems a code from SignInCallback for a pair of tokens. The
+// account is checked again, it may have been moderated in the meantime.
+func (s *OAuthService) Exchange(ctx context.Context, code string, client dto.ClientInfo) (*dto.ExchangeResponse, error) {
+	row, err := s.queries.ConsumeOAuthExchangeCode(ctx, hashToken(code))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidExchangeCode
+		}
+		return nil, err
+	}
+
+	user, err := getUserByID(ctx, s.queries, row.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authSvc.checkSignInAllowed(user); err != nil {
+		return nil, err
+	}
+	tokens, err := s.authSvc.issueTokens(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ExchangeResponse{AuthResponse: *tokens, ReturnTo: row.ReturnTo.String}, nil
+}
+
+func (s *OAuthService) createExchangeCode(ctx context.Context, userID int32, returnTo string) (string, error) {
+	code, hash, err := newSecretToken()
+	if err != nil {
+		return "", err
+	}
+	if err := s.queries.CreateOAuthExchangeCode(ctx, db.CreateOAuthExchangeCodeParams{
+		CodeHash:  hash,
+		UserID:    userID,
+		ReturnTo:  optText(returnTo),
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(oauthExchangeDuration), Valid: true},
+	}); err != nil {
+		return "", err
+	}
+	return code, nil
+}
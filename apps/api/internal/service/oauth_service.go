@@ -2,47 +2,402 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
 
 	"github.com/MassoudJavadi/filmophilia/api/internal/db"
 	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/mapper"
 	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/oauth"
+	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/token"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const (
+	// How long a link started by a signed-in user stays usable
+	linkTokenDuration = 10 * time.Minute
+
+	usernameMaxLen   = 40
+	usernameAttempts = 10
+)
+
+var (
+	ErrOAuthEmailUnverified   = errors.New("the provider has not verified this email address")
+	ErrAccountLinkedElsewhere = errors.New("this provider account is linked to another user")
+	ErrProviderAlreadyLinked  = errors.New("another account of this provider is already linked")
+	ErrAccountNotLinked       = errors.New("provider is not linked")
+	ErrLastSignInMethod       = errors.New("cannot unlink the only way to sign in, set a password first")
+	ErrInvalidLinkToken       = errors.New("invalid or expired link token")
+	ErrUnknownProvider        = errors.New("unknown provider")
+	ErrProviderRejected       = errors.New("sign-in with the provider failed")
+)
+
+// providerIdentity is who a provider says the user is
+type providerIdentity struct {
+	Provider string
+	oauth.UserInfo
+}
+
+// OAuthService signs users in through external providers. Provider accounts
+// are tied to users through the accounts table, keyed by the provider's stable
+// subject id rather than by email, which the user can change on either side.
 type OAuthService struct {
+	pool       *pgxpool.Pool
 	queries    *db.Queries
 	authSvc    *AuthService // We reuse AuthService to issue tokens
-	googleMgr  *oauth.GoogleManager
+	jwt        *token.JWTManager
+	providers  *oauth.Registry
+	config     OAuthConfig
+}
+
+func NewOAuthService(pool *pgxpool.Pool, q *db.Queries, a *AuthService, j *token.JWTManager, providers *oauth.Registry, cfg OAuthConfig) *OAuthService {
+	return &OAuthService{pool: pool, queries: q, authSvc: a, jwt: j, providers: providers, config: cfg}
+}
+
+func (s *OAuthService) provider(name string) (oauth.Provider, error) {
+	p, ok := s.providers.Get(name)
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return p, nil
+}
+
+// StartFlow returns a fresh flow for the provider and the URL to send the
+// browser to. returnTo, if given, must pass the whitelist and is handed back
+// when the sign-in completes.
+func (s *OAuthService) StartFlow(ctx context.Context, provider, returnTo string) (oauth.Flow, string, error) {
+	p, err := s.provider(provider)
+	if err != nil {
+		return oauth.Flow{}, "", err
+	}
+	if !s.config.allowsReturnTo(returnTo) {
+		return oauth.Flow{}, "", ErrInvalidReturnTo
+	}
+	flow, err := oauth.NewFlow(provider)
+	if err != nil {
+		return oauth.Flow{}, "", err
+	}
+	flow.ReturnTo = returnTo
+	url, err := p.AuthCodeURL(ctx, flow)
+	if err != nil {
+		return oauth.Flow{}, "", err
+	}
+	return flow, url, nil
+}
+
+// SignInCallback signs the user in and returns a one-time code the frontend
+// trades for tokens with Exchange. No tokens are issued until then, so none
+// ever appear in a URL.
+func (s *OAuthService) SignInCallback(ctx context.Context, code string, flow oauth.Flow) (string, error) {
+	id, err := s.identify(ctx, code, flow)
+	if err != nil {
+		return "", err
+	}
+	user, err := s.signIn(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return s.createExchangeCode(ctx, user.ID, flow.ReturnTo)
+}
+
+// LinkCallback attaches the provider account behind code to a signed-in user
+func (s *OAuthService) LinkCallback(ctx context.Context, userID int32, code string, flow oauth.Flow) (*dto.AccountResponse, error) {
+	id, err := s.identify(ctx, code, flow)
+	if err != nil {
+		return nil, err
+	}
+	return s.link(ctx, userID, id)
+}
+
+// identify redeems the code with the provider the flow was started for.
+// Whatever the provider rejects is reported as ErrProviderRejected.
+func (s *OAuthService) identify(ctx context.Context, code string, flow oauth.Flow) (providerIdentity, error) {
+	p, err := s.provider(flow.Provider)
+	if err != nil {
+		return providerIdentity{}, err
+	}
+	info, err := p.Identify(ctx, code, flow)
+	if err != nil {
+		return providerIdentity{}, fmt.Errorf("%w: %s: %w", ErrProviderRejected, flow.Provider, err)
+	}
+	if info.Subject == "" {
+		return providerIdentity{}, fmt.Errorf("%w: %s: no subject", ErrProviderRejected, flow.Provider)
+	}
+	return providerIdentity{Provider: flow.Provider, UserInfo: *info}, nil
 }
 
-func NewOAuthService(q *db.Queries, a *AuthService, g *oauth.GoogleManager) *OAuthService {
-	return &OAuthService{queries: q, authSvc: a, googleMgr: g}
+// StartLink starts linking a provider to a signed-in user. Besides the flow
+// it returns a short-lived link token naming the user; both are set as
+// cookies on the authenticated request itself, so a link can't be started
+// from a URL handed to someone else's browser.
+func (s *OAuthService) StartLink(ctx context.Context, userID int32, provider, returnTo string) (oauth.Flow, string, string, error) {
+	flow, authURL, err := s.StartFlow(ctx, provider, returnTo)
+	if err != nil {
+		return oauth.Flow{}, "", "", err
+	}
+	t, err := s.jwt.GeneratePurpose(token.PurposeLinkAccount, userID, "", linkTokenDuration)
+	if err != nil {
+		return oauth.Flow{}, "", "", err
+	}
+	return flow, authURL, t, nil
 }
 
-func (s *OAuthService) GetGoogleAuthURL(state string) string {
-	return s.googleMgr.GetAuthURL(state)
+func (s *OAuthService) VerifyLinkToken(t string) (int32, error) {
+	userID, _, err := s.jwt.VerifyPurpose(token.PurposeLinkAccount, t)
+	if err != nil {
+		return 0, ErrInvalidLinkToken
+	}
+	return userID, nil
 }
 
-func (s *OAuthService) HandleGoogleCallback(ctx context.Context, code string) (*dto.AuthResponse, error) {
-	// 1. Get info from Google API
-	gUser, err := s.googleMgr.GetUserInfo(ctx, code)
+func (s *OAuthService) ListAccounts(ctx context.Context, userID int32) ([]dto.AccountResponse, error) {
+	accounts, err := s.queries.ListUserAccounts(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
+	return mapper.ToAccountResponses(accounts), nil
+}
+
+// Unlink removes a provider unless it is the user's only way to sign in
+func (s *OAuthService) Unlink(ctx context.Context, userID int32, provider string) error {
+	user, err := getUserByID(ctx, s.queries, userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.queries.WithTx(tx)
+
+	n, err := qtx.DeleteUserAccount(ctx, db.DeleteUserAccountParams{UserID: userID, Provider: provider})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrAccountNotLinked
+	}
+
+	if !user.PasswordHash.Valid {
+		remaining, err := qtx.CountUserAccounts(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if remaining == 0 {
+			return ErrLastSignInMethod
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// signIn finds the user linked to the provider account, linking or creating
+// one by email the first time it is seen
+func (s *OAuthService) signIn(ctx context.Context, id providerIdentity) (db.User, error) {
+	var user db.User
+	account, err := s.queries.GetAccountByProvider(ctx, db.GetAccountByProviderParams{
+		Provider:          id.Provider,
+		ProviderAccountID: id.Subject,
+	})
+	switch {
+	case err == nil:
+		user, err = getUserByID(ctx, s.queries, account.UserID)
+	case errors.Is(err, pgx.ErrNoRows):
+		user, err = s.linkOrCreate(ctx, id)
+	}
+	if err != nil {
+		return db.User{}, err
+	}
+
+	if err := s.authSvc.checkSignInAllowed(user); err != nil {
+		return db.User{}, err
+	}
+	return user, nil
+}
+
+// linkOrCreate handles a provider account seen for the first time. Matching by
+// email is only safe when the provider has verified the address. A local
+// account that never verified the same address may have been registered by
+// someone else, so its password and sessions are dropped as it is claimed.
+func (s *OAuthService) linkOrCreate(ctx context.Context, id providerIdentity) (db.User, error) {
+	if !id.EmailVerified || id.Email == "" {
+		return db.User{}, ErrOAuthEmailUnverified
+	}
 
-	// 2. Find or Create user in our DB
-	user, err := s.queries.GetUserByEmail(ctx, gUser.Email)
+	tx, err := s.pool.Begin(ctx)
 	if err != nil {
-		// User doesn't exist, create them (Password is empty for OAuth users)
-		user, err = s.queries.CreateUser(ctx, db.CreateUserParams{
-			Email:        gUser.Email,
-			Username:     gUser.Email, // Simplified for now
-			PasswordHash: "",          // No password for OAuth
+		return db.User{}, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.queries.WithTx(tx)
+
+	user, err := qtx.GetUserByEmail(ctx, id.Email)
+	switch {
+	case err == nil:
+		if !user.IsVerified {
+			if user, err = s.claimUnverified(ctx, qtx, user, id.Provider); err != nil {
+				return db.User{}, err
+			}
+		}
+	case errors.Is(err, pgx.ErrNoRows):
+		username, err := uniqueUsername(ctx, qtx, id.Name, id.Email)
+		if err != nil {
+			return db.User{}, err
+		}
+		user, err = qtx.CreateOAuthUser(ctx, db.CreateOAuthUserParams{
+			Email:       id.Email,
+			Username:    username,
+			DisplayName: optText(strings.TrimSpace(id.Name)),
+			AvatarUrl:   optText(id.Picture),
 		})
 		if err != nil {
-			return nil, err
+			return db.User{}, err
+		}
+	default:
+		return db.User{}, err
+	}
+
+	if err := s.createAccount(ctx, qtx, &user, id); err != nil {
+		return db.User{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return db.User{}, err
+	}
+	return user, nil
+}
+
+func (s *OAuthService) claimUnverified(ctx context.Context, q *db.Queries, user db.User, provider string) (db.User, error) {
+	if err := q.SetStatusChangeReason(ctx, "email verified by "+provider); err != nil {
+		return db.User{}, err
+	}
+	user, err := q.VerifyUserEmail(ctx, db.VerifyUserEmailParams{ID: user.ID, Email: user.Email})
+	if err != nil {
+		return db.User{}, err
+	}
+	if err := q.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{ID: user.ID}); err != nil {
+		return db.User{}, err
+	}
+	if err := q.DeleteUserSessions(ctx, user.ID); err != nil {
+		return db.User{}, err
+	}
+	user.PasswordHash = pgtype.Text{}
+	return user, nil
+}
+
+// link attaches a provider account to an existing user; linking the same
+// account again is a no-op
+func (s *OAuthService) link(ctx context.Context, userID int32, id providerIdentity) (*dto.AccountResponse, error) {
+	user, err := getUserByID(ctx, s.queries, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.queries.GetAccountByProvider(ctx, db.GetAccountByProviderParams{
+		Provider:          id.Provider,
+		ProviderAccountID: id.Subject,
+	})
+	if err == nil {
+		if existing.UserID != userID {
+			return nil, ErrAccountLinkedElsewhere
+		}
+		resp := mapper.ToAccountResponse(existing)
+		return &resp, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	if err := s.createAccount(ctx, s.queries, &user, id); err != nil {
+		return nil, err
+	}
+	account, err := s.queries.GetAccountByProvider(ctx, db.GetAccountByProviderParams{
+		Provider:          id.Provider,
+		ProviderAccountID: id.Subject,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := mapper.ToAccountResponse(account)
+	return &resp, nil
+}
+
+// createAccount links the provider account and imports its picture when the
+// user has no avatar yet
+func (s *OAuthService) createAccount(ctx context.Context, q *db.Queries, user *db.User, id providerIdentity) error {
+	if _, err := q.CreateAccount(ctx, db.CreateAccountParams{
+		UserID:            user.ID,
+		Provider:          id.Provider,
+		ProviderAccountID: id.Subject,
+	}); err != nil {
+		if strings.Contains(err.Error(), "accounts_user_provider_key") {
+			return ErrProviderAlreadyLinked
+		}
+		if strings.Contains(err.Error(), "accounts_provider_provider_account_id_key") {
+			return ErrAccountLinkedElsewhere
+		}
+		return err
+	}
+
+	if id.Picture != "" && !user.AvatarUrl.Valid {
+		if err := q.SetUserAvatarIfEmpty(ctx, db.SetUserAvatarIfEmptyParams{ID: user.ID, AvatarUrl: optText(id.Picture)}); err != nil {
+			return err
+		}
+		user.AvatarUrl = optText(id.Picture)
+	}
+	return nil
+}
+
+// uniqueUsername derives a username from the provider name, falling back to
+// the email's local part, and adds a numeric suffix until it is free
+func uniqueUsername(ctx context.Context, q *db.Queries, name, email string) (string, error) {
+	base := usernameFrom(name)
+	if len(base) < 3 {
+		local, _, _ := strings.Cut(email, "@")
+		base = usernameFrom(local)
+	}
+	if len(base) < 3 {
+		base = "user"
+	}
+
+	candidate := base
+	for range usernameAttempts {
+		exists, err := q.UsernameExists(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
 		}
+		candidate = fmt.Sprintf("%s_%d", base, rand.IntN(10000))
 	}
+	return "", fmt.Errorf("no free username for %q after %d attempts", base, usernameAttempts)
+}
 
-	// 3. Reuse our standard token issuance logic
-	return s.authSvc.issueTokens(ctx, user)
-}
\ No newline at end of file
+// usernameFrom keeps lowercase ASCII letters and digits, joining words with underscores
+func usernameFrom(s string) string {
+	var b strings.Builder
+	sep := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if sep && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			sep = false
+			b.WriteRune(r)
+		default:
+			sep = true
+		}
+	}
+	out := b.String()
+	if len(out) > usernameMaxLen {
+		out = strings.TrimRight(out[:usernameMaxLen], "_")
+	}
+	return out
+}
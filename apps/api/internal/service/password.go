@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/pkg/mailer"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const passwordResetDuration = time.Hour
+
+var (
+	ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+	ErrNoPassword        = errors.New("account has no password yet, use forgot password to set one")
+	ErrPasswordTooLong   = errors.New("password must be at most 72 bytes")
+)
+
+// ForgotPassword mails a single-use reset link. Like ResendVerification it
+// reports nothing back: unknown, moderated and rate-limited addresses are
+// silently skipped so the endpoint cannot be used to probe for accounts. All
+// of the work happens after the response, so a known address does not answer
+// any slower than an unknown one either.
+func (s *AuthService) ForgotPassword(ctx context.Context, email string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), mailSendTimeout)
+		defer cancel()
+		if err := s.sendPasswordReset(ctx, email); err != nil {
+			log.Printf("password reset for %s: %v", email, err)
+		}
+	}()
+}
+
+func (s *AuthService) sendPasswordReset(ctx context.Context, email string) error {
+	user, err := s.queries.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if user.Status == db.UserStatusBANNED || user.Status == db.UserStatusSUSPENDED {
+		return nil
+	}
+
+	recent, err := s.queries.HasRecentPasswordResetToken(ctx, db.HasRecentPasswordResetTokenParams{
+		UserID:          user.ID,
+		CooldownSeconds: int32(passwordResetCooldown / time.Second),
+	})
+	if err != nil {
+		return err
+	}
+	if recent {
+		return nil
+	}
+
+	resetToken, hash, err := newSecretToken()
+	if err != nil {
+		return err
+	}
+	if err := s.queries.CreatePasswordResetToken(ctx, db.CreatePasswordResetTokenParams{
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(passwordResetDuration), Valid: true},
+	}); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your Filmophilia password",
+		Body: "Hi " + user.Username + ",\n\n" +
+			"Use this link to choose a new password:\n\n" +
+			s.config.ResetURL + "?token=" + url.QueryEscape(resetToken) + "\n\n" +
+			"The link expires in one hour and works once. If you did not ask for it, you can ignore this email.\n",
+	})
+}
+
+// ResetPassword redeems a reset token, sets the new password and signs the
+// user out everywhere. Any other outstanding reset links stop working too.
+func (s *AuthService) ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) error {
+	hash, err := hashPassword(req.Password)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.queries.WithTx(tx)
+
+	userID, err := qtx.ConsumePasswordResetToken(ctx, hashToken(req.Token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	if err := s.replacePassword(ctx, qtx, userID, string(hash)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ChangePassword replaces the password of a signed-in user after checking the
+// current one. Every session is revoked, including the caller's, and a fresh
+// pair of tokens is returned so the caller stays signed in.
+func (s *AuthService) ChangePassword(ctx context.Context, userID int32, req dto.ChangePasswordRequest, client dto.ClientInfo) (*dto.AuthResponse, error) {
+	user, err := getUserByID(ctx, s.queries, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.PasswordHash.Valid {
+		return nil, ErrNoPassword
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash.String), []byte(req.CurrentPassword)); err != nil {
+		return nil, ErrIncorrectPassword
+	}
+
+	hash, err := hashPassword(req.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.replacePassword(ctx, s.queries.WithTx(tx), userID, string(hash)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	user.PasswordHash = pgtype.Text{String: string(hash), Valid: true}
+	return s.issueTokens(ctx, user, client)
+}
+
+// replacePassword stores a new hash and revokes what the old password could have granted
+func (s *AuthService) replacePassword(ctx context.Context, q *db.Queries, userID int32, hash string) error {
+	if err := q.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{ID: userID, PasswordHash: pgtype.Text{String: hash, Valid: true}}); err != nil {
+		return err
+	}
+	if err := q.DeleteUserSessions(ctx, userID); err != nil {
+		return err
+	}
+	return q.DeleteUserPasswordResetTokens(ctx, userID)
+}
+
+// newSecretToken returns a random single-use token for the user and the hash that is stored
+func newSecretToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	t := base64.RawURLEncoding.EncodeToString(b)
+	return t, hashToken(t), nil
+}
+
+// hashPassword bcrypts a new password. bcrypt refuses input over 72 bytes;
+// the DTOs' max=72 counts characters, so a multi-byte password can still
+// exceed it.
+func hashPassword(password string) ([]byte, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		return nil, ErrPasswordTooLong
+	}
+	return hash, err
+}
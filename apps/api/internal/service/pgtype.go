@@ -0,0 +1,18 @@
+package service
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// optText maps an empty string to SQL NULL
+func optText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+// optInt4 maps a nil pointer to SQL NULL
+func optInt4(i *int32) pgtype.Int4 {
+	if i == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: *i, Valid: true}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/mapper"
+)
+
+var ErrRatingNotFound = errors.New("rating not found")
+
+type RatingService struct {
+	queries *db.Queries
+}
+
+func NewRatingService(q *db.Queries) *RatingService {
+	return &RatingService{queries: q}
+}
+
+// RateMovie creates or replaces the user's score for a movie. The stats trigger
+// refreshes the movie aggregate in the same statement, so it is read back here.
+func (s *RatingService) RateMovie(ctx context.Context, userID int32, slug string, score int32) (*dto.RatingResponse, error) {
+	movie, err := getMovieBySlug(ctx, s.queries, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	rating, err := s.queries.UpsertRating(ctx, db.UpsertRatingParams{
+		UserID:  userID,
+		MovieID: movie.ID,
+		Score:   score,
+	})
+	if err != nil {
+		return nil, err
+	}
+	recordActivity(ctx, s.queries, userID, dto.ActivityRated, db.EntityTypeMOVIE, movie.ID, map[string]any{"score": score})
+
+	movie, err = s.queries.GetMovieByID(ctx, movie.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !statsIncludeRating(movie) {
+		return nil, fmt.Errorf("movie %d: rating stats were not refreshed by the ratings trigger", movie.ID)
+	}
+
+	resp := mapper.ToRatingResponse(rating, movie)
+	return &resp, nil
+}
+
+// statsIncludeRating reports whether a movie read back after an upsert counts
+// at least that rating. It is false when the stats trigger did not update the
+// movie row, which would otherwise go out as stale stats.
+func statsIncludeRating(m db.Movie) bool {
+	return m.UserRatingCount.Valid && m.UserRatingCount.Int32 > 0 && m.UserAvgRating.Valid
+}
+
+func (s *RatingService) DeleteRating(ctx context.Context, userID int32, slug string) (*dto.MovieStatsResponse, error) {
+	movie, err := getMovieBySlug(ctx, s.queries, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := s.queries.DeleteRating(ctx, db.DeleteRatingParams{UserID: userID, MovieID: movie.ID})
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrRatingNotFound
+	}
+
+	movie, err = s.queries.GetMovieByID(ctx, movie.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := mapper.ToMovieStatsResponse(movie)
+	return &stats, nil
+}
+
+// ListUserRatings lists a user's ratings; viewerID is 0 for anonymous callers
+func (s *RatingService) ListUserRatings(ctx context.Context, username string, q dto.UserRatingsQuery, viewerID int32) (dto.PaginatedResponse[dto.UserRatingResponse], error) {
+	user, err := getVisibleUser(ctx, s.queries, username, viewerID)
+	if err != nil {
+		return dto.PaginatedResponse[dto.UserRatingResponse]{}, err
+	}
+
+	total, err := s.queries.CountUserRatings(ctx, user.ID)
+	if err != nil {
+		return dto.PaginatedResponse[dto.UserRatingResponse]{}, err
+	}
+
+	rows, err := s.queries.ListUserRatings(ctx, db.ListUserRatingsParams{
+		UserID: user.ID,
+		Sort:   q.Sort,
+		Limit:  q.Limit(),
+		Offset: q.Offset(),
+	})
+	if err != nil {
+		return dto.PaginatedResponse[dto.UserRatingResponse]{}, err
+	}
+
+	return dto.NewPaginatedResponse(mapper.ToUserRatingResponses(rows), q.PaginationQuery, total), nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestStatsIncludeRating(t *testing.T) {
+	movie := func(avg pgtype.Float4, count pgtype.Int4) db.Movie {
+		return db.Movie{ID: 1, UserAvgRating: avg, UserRatingCount: count}
+	}
+
+	tests := []struct {
+		name  string
+		movie db.Movie
+		want  bool
+	}{
+		{"refreshed", movie(pgtype.Float4{Float32: 8, Valid: true}, pgtype.Int4{Int32: 1, Valid: true}), true},
+		{"refreshed with other ratings", movie(pgtype.Float4{Float32: 6.5, Valid: true}, pgtype.Int4{Int32: 12, Valid: true}), true},
+		// The column defaults: the trigger never touched the row
+		{"untouched defaults", movie(pgtype.Float4{Float32: 0, Valid: true}, pgtype.Int4{Int32: 0, Valid: true}), false},
+		{"null count", movie(pgtype.Float4{Float32: 8, Valid: true}, pgtype.Int4{}), false},
+		{"null average", movie(pgtype.Float4{}, pgtype.Int4{Int32: 1, Valid: true}), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statsIncludeRating(tt.movie); got != tt.want {
+				t.Errorf("statsIncludeRating = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
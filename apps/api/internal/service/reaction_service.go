@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ReactionService struct {
+	queries       *db.Queries
+	notifications *NotificationService
+}
+
+func NewReactionService(q *db.Queries, ns *NotificationService) *ReactionService {
+	return &ReactionService{queries: q, notifications: ns}
+}
+
+// ReactToReview sets or changes the user's reaction on a review
+func (s *ReactionService) ReactToReview(ctx context.Context, userID, reviewID int32, reaction string) (*dto.ReactionSummary, error) {
+	review, err := s.checkReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.queries.UpsertReviewReaction(ctx, db.UpsertReviewReactionParams{
+		UserID:   userID,
+		ReviewID: pgtype.Int4{Int32: reviewID, Valid: true},
+		Type:     db.ReactionType(reaction),
+	}); err != nil {
+		return nil, err
+	}
+	s.notifications.NotifyReviewReaction(ctx, userID, review, reaction)
+
+	return s.reviewSummary(ctx, reviewID, userID)
+}
+
+// ClearReviewReaction removes the user's reaction; clearing twice is not an error
+func (s *ReactionService) ClearReviewReaction(ctx context.Context, userID, reviewID int32) (*dto.ReactionSummary, error) {
+	if _, err := s.checkReview(ctx, reviewID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.queries.DeleteReviewReaction(ctx, db.DeleteReviewReactionParams{
+		UserID:   userID,
+		ReviewID: pgtype.Int4{Int32: reviewID, Valid: true},
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.reviewSummary(ctx, reviewID, userID)
+}
+
+// ReactToComment sets or changes the user's reaction on a live comment
+func (s *ReactionService) ReactToComment(ctx context.Context, userID, commentID int32, reaction string) (*dto.ReactionSummary, error) {
+	comment, err := s.checkComment(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.queries.UpsertCommentReaction(ctx, db.UpsertCommentReactionParams{
+		UserID:    userID,
+		CommentID: pgtype.Int4{Int32: commentID, Valid: true},
+		Type:      db.ReactionType(reaction),
+	}); err != nil {
+		return nil, err
+	}
+	s.notifications.NotifyCommentReaction(ctx, userID, comment, reaction)
+
+	return s.commentSummary(ctx, commentID, userID)
+}
+
+// ClearCommentReaction removes the user's reaction; clearing twice is not an error
+func (s *ReactionService) ClearCommentReaction(ctx context.Context, userID, commentID int32) (*dto.ReactionSummary, error) {
+	if _, err := s.checkComment(ctx, commentID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.queries.DeleteCommentReaction(ctx, db.DeleteCommentReactionParams{
+		UserID:    userID,
+		CommentID: pgtype.Int4{Int32: commentID, Valid: true},
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.commentSummary(ctx, commentID, userID)
+}
+
+func (s *ReactionService) checkReview(ctx context.Context, reviewID int32) (db.Review, error) {
+	review, err := s.queries.GetReviewByID(ctx, reviewID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Review{}, ErrReviewNotFound
+		}
+		return db.Review{}, err
+	}
+	return review, nil
+}
+
+// checkComment only accepts live comments; soft-deleted ones take no reactions
+func (s *ReactionService) checkComment(ctx context.Context, commentID int32) (db.Comment, error) {
+	comment, err := s.queries.GetCommentByID(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Comment{}, ErrCommentNotFound
+		}
+		return db.Comment{}, err
+	}
+	if comment.DeletedAt.Valid {
+		return db.Comment{}, ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+func (s *ReactionService) reviewSummary(ctx context.Context, reviewID, viewerID int32) (*dto.ReactionSummary, error) {
+	summaries, err := reviewReactionSummaries(ctx, s.queries, []int32{reviewID}, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	summary := summaries[reviewID]
+	return &summary, nil
+}
+
+func (s *ReactionService) commentSummary(ctx context.Context, commentID, viewerID int32) (*dto.ReactionSummary, error) {
+	summaries, err := commentReactionSummaries(ctx, s.queries, []int32{commentID}, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	summary := summaries[commentID]
+	return &summary, nil
+}
+
+// reviewReactionSummaries loads breakdowns for a batch of reviews in two queries.
+// viewerID 0 means anonymous, in which case Mine is left nil.
+func reviewReactionSummaries(ctx context.Context, q *db.Queries, ids []int32, viewerID int32) (map[int32]dto.ReactionSummary, error) {
+	out := emptySummaries(ids)
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	counts, err := q.CountReviewReactions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range counts {
+		addCount(out, c.ReviewID.Int32, c.Type, c.Count)
+	}
+
+	if viewerID != 0 {
+		mine, err := q.ListUserReviewReactions(ctx, db.ListUserReviewReactionsParams{UserID: viewerID, ReviewIds: ids})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range mine {
+			setMine(out, m.ReviewID.Int32, m.Type)
+		}
+	}
+	return out, nil
+}
+
+// commentReactionSummaries is the comment counterpart of reviewReactionSummaries
+func commentReactionSummaries(ctx context.Context, q *db.Queries, ids []int32, viewerID int32) (map[int32]dto.ReactionSummary, error) {
+	out := emptySummaries(ids)
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	counts, err := q.CountCommentReactions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range counts {
+		addCount(out, c.CommentID.Int32, c.Type, c.Count)
+	}
+
+	if viewerID != 0 {
+		mine, err := q.ListUserCommentReactions(ctx, db.ListUserCommentReactionsParams{UserID: viewerID, CommentIds: ids})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range mine {
+			setMine(out, m.CommentID.Int32, m.Type)
+		}
+	}
+	return out, nil
+}
+
+func emptySummaries(ids []int32) map[int32]dto.ReactionSummary {
+	out := make(map[int32]dto.ReactionSummary, len(ids))
+	for _, id := range ids {
+		out[id] = dto.NewReactionSummary()
+	}
+	return out
+}
+
+func addCount(m map[int32]dto.ReactionSummary, id int32, t db.ReactionType, n int64) {
+	s := m[id]
+	s.Counts[string(t)] += n
+	s.Total += n
+	m[id] = s
+}
+
+func setMine(m map[int32]dto.ReactionSummary, id int32, t db.ReactionType) {
+	s := m[id]
+	mine := string(t)
+	s.Mine = &mine
+	m[id] = s
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/mapper"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrReviewNotFound = errors.New("review not found")
+	ErrReviewExists   = errors.New("you have already reviewed this movie")
+	ErrNotReviewOwner = errors.New("you can only modify your own reviews")
+)
+
+type ReviewService struct {
+	queries       *db.Queries
+	notifications *NotificationService
+}
+
+func NewReviewService(q *db.Queries, ns *NotificationService) *ReviewService {
+	return &ReviewService{queries: q, notifications: ns}
+}
+
+func (s *ReviewService) Create(ctx context.Context, userID int32, slug string, req dto.CreateReviewRequest) (*dto.ReviewResponse, error) {
+	movie, err := getMovieBySlug(ctx, s.queries, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := s.queries.CreateReview(ctx, db.CreateReviewParams{
+		UserID:  userID,
+		MovieID: movie.ID,
+		Title:   optText(strings.TrimSpace(req.Title)),
+		Content: req.Content,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "reviews_user_id_movie_id_key") {
+			return nil, ErrReviewExists
+		}
+		return nil, err
+	}
+	recordActivity(ctx, s.queries, userID, dto.ActivityReviewed, db.EntityTypeREVIEW, review.ID, nil)
+	s.notifications.NotifyNewReview(ctx, review, movie)
+
+	return s.Get(ctx, review.ID, userID)
+}
+
+// Get returns a single review; viewerID personalises reactions and is 0 for anonymous callers
+func (s *ReviewService) Get(ctx context.Context, id, viewerID int32) (*dto.ReviewResponse, error) {
+	row, err := s.queries.GetReviewView(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, err
+	}
+
+	items := []dto.ReviewResponse{mapper.ToReviewResponse(row)}
+	if err := s.attachReactions(ctx, items, viewerID); err != nil {
+		return nil, err
+	}
+	return &items[0], nil
+}
+
+func (s *ReviewService) Update(ctx context.Context, userID, id int32, req dto.UpdateReviewRequest) (*dto.ReviewResponse, error) {
+	if _, err := s.getOwnedReview(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	params := db.UpdateReviewParams{ID: id}
+	if req.Title != nil {
+		params.Title = pgtype.Text{String: strings.TrimSpace(*req.Title), Valid: true}
+	}
+	if req.Content != nil {
+		params.Content = pgtype.Text{String: *req.Content, Valid: true}
+	}
+	if _, err := s.queries.UpdateReview(ctx, params); err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, id, userID)
+}
+
+func (s *ReviewService) Delete(ctx context.Context, userID, id int32) error {
+	if _, err := s.getOwnedReview(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.queries.DeleteReview(ctx, id)
+}
+
+func (s *ReviewService) ListByMovie(ctx context.Context, slug string, q dto.MovieReviewsQuery, viewerID int32) (dto.PaginatedResponse[dto.ReviewResponse], error) {
+	movie, err := getMovieBySlug(ctx, s.queries, slug)
+	if err != nil {
+		return dto.PaginatedResponse[dto.ReviewResponse]{}, err
+	}
+
+	total, err := s.queries.CountMovieReviews(ctx, movie.ID)
+	if err != nil {
+		return dto.PaginatedResponse[dto.ReviewResponse]{}, err
+	}
+
+	rows, err := s.queries.ListMovieReviews(ctx, db.ListMovieReviewsParams{
+		MovieID: movie.ID,
+		Sort:    q.Sort,
+		Limit:   q.Limit(),
+		Offset:  q.Offset(),
+	})
+	if err != nil {
+		return dto.PaginatedResponse[dto.ReviewResponse]{}, err
+	}
+
+	items := make([]dto.ReviewResponse, 0, len(rows))
+	for _, r := range rows {
+		items = append(items, mapper.ToReviewResponse(db.GetReviewViewRow(r)))
+	}
+	if err := s.attachReactions(ctx, items, viewerID); err != nil {
+		return dto.PaginatedResponse[dto.ReviewResponse]{}, err
+	}
+	return dto.NewPaginatedResponse(items, q.PaginationQuery, total), nil
+}
+
+func (s *ReviewService) ListByUser(ctx context.Context, username string, q dto.PaginationQuery, viewerID int32) (dto.PaginatedResponse[dto.ReviewResponse], error) {
+	user, err := getVisibleUser(ctx, s.queries, username, viewerID)
+	if err != nil {
+		return dto.PaginatedResponse[dto.ReviewResponse]{}, err
+	}
+
+	total, err := s.queries.CountUserReviews(ctx, user.ID)
+	if err != nil {
+		return dto.PaginatedResponse[dto.ReviewResponse]{}, err
+	}
+
+	rows, err := s.queries.ListUserReviews(ctx, db.ListUserReviewsParams{
+		UserID: user.ID,
+		Limit:  q.Limit(),
+		Offset: q.Offset(),
+	})
+	if err != nil {
+		return dto.PaginatedResponse[dto.ReviewResponse]{}, err
+	}
+
+	items := make([]dto.ReviewResponse, 0, len(rows))
+	for _, r := range rows {
+		items = append(items, mapper.ToReviewResponse(db.GetReviewViewRow(r)))
+	}
+	if err := s.attachReactions(ctx, items, viewerID); err != nil {
+		return dto.PaginatedResponse[dto.ReviewResponse]{}, err
+	}
+	return dto.NewPaginatedResponse(items, q, total), nil
+}
+
+// attachReactions fills in the reaction breakdown of every review in place
+func (s *ReviewService) attachReactions(ctx context.Context, items []dto.ReviewResponse, viewerID int32) error {
+	ids := make([]int32, 0, len(items))
+	for _, r := range items {
+		ids = append(ids, r.ID)
+	}
+
+	summaries, err := reviewReactionSummaries(ctx, s.queries, ids, viewerID)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		items[i].Reactions = summaries[items[i].ID]
+	}
+	return nil
+}
+
+// getOwnedReview loads a review and checks that userID wrote it
+func (s *ReviewService) getOwnedReview(ctx context.Context, userID, id int32) (db.Review, error) {
+	review, err := s.queries.GetReviewByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Review{}, ErrReviewNotFound
+		}
+		return db.Review{}, err
+	}
+	if review.UserID != userID {
+		return db.Review{}, ErrNotReviewOwner
+	}
+	return review, nil
+}
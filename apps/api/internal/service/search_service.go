@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/mapper"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	envSearchThreshold = "SEARCH_SIMILARITY_THRESHOLD"
+
+	// pg_trgm's own default; low enough to catch typos like "Godfater"
+	defaultSearchThreshold = 0.3
+
+	// pg_trgm's own default for <%, used by prefix search
+	defaultWordSimilarityThreshold = 0.6
+
+	// Below this the trigram operators match nearly every row
+	minSearchThreshold = 0.1
+)
+
+type SearchService struct {
+	pool      *pgxpool.Pool
+	queries   *db.Queries
+	threshold float64
+}
+
+func NewSearchService(pool *pgxpool.Pool, q *db.Queries) *SearchService {
+	threshold := defaultSearchThreshold
+	if v := os.Getenv(envSearchThreshold); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f >= minSearchThreshold && f <= 1 {
+			threshold = f
+		} else {
+			log.Printf("ignoring invalid %s=%q, using %.2f", envSearchThreshold, v, threshold)
+		}
+	}
+	return &SearchService{pool: pool, queries: q, threshold: threshold}
+}
+
+// Search runs a typo-tolerant trigram search over movies and persons and merges
+// both result sets into a single ranking. Prefix mode ranks by word_similarity,
+// which scores the query against the best matching part of a title instead of
+// the whole of it, so a few typed letters already find the movie.
+func (s *SearchService) Search(ctx context.Context, req dto.SearchQuery) (*dto.SearchResponse, error) {
+	query := strings.TrimSpace(req.Q)
+	prefix := req.Mode == dto.SearchModePrefix
+	threshold := s.threshold
+	if prefix {
+		threshold = defaultWordSimilarityThreshold
+	}
+	if req.Threshold != nil {
+		threshold = *req.Threshold
+	}
+
+	// The threshold is a GUC, so it is set with SET LOCAL semantics inside a
+	// transaction to keep it from leaking into other pooled connections.
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.queries.WithTx(tx)
+
+	setThreshold := qtx.SetSimilarityThreshold
+	if prefix {
+		setThreshold = qtx.SetWordSimilarityThreshold
+	}
+	if err := setThreshold(ctx, strconv.FormatFloat(threshold, 'f', -1, 64)); err != nil {
+		return nil, err
+	}
+
+	counts, err := countSearchResults(ctx, qtx, query, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []dto.SearchResult{}
+	if req.Type == dto.SearchTypeAll || req.Type == dto.SearchTypeMovie {
+		movies, err := searchMovies(ctx, qtx, db.SearchMoviesParams{Query: query, Limit: req.Limit}, prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range movies {
+			results = append(results, mapper.ToMovieSearchResult(m))
+		}
+	}
+	if req.Type == dto.SearchTypeAll || req.Type == dto.SearchTypePerson {
+		persons, err := searchPersons(ctx, qtx, db.SearchPersonsParams{Query: query, Limit: req.Limit}, prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range persons {
+			results = append(results, mapper.ToPersonSearchResult(p))
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > int(req.Limit) {
+		results = results[:req.Limit]
+	}
+
+	return &dto.SearchResponse{
+		Query:   query,
+		Results: results,
+		Facets: map[string]int64{
+			dto.SearchTypeMovie:  counts.MovieCount,
+			dto.SearchTypePerson: counts.PersonCount,
+		},
+	}, nil
+}
+
+func countSearchResults(ctx context.Context, q *db.Queries, query string, prefix bool) (db.CountSearchResultsRow, error) {
+	if !prefix {
+		return q.CountSearchResults(ctx, query)
+	}
+	row, err := q.CountSearchResultsByPrefix(ctx, query)
+	return db.CountSearchResultsRow(row), err
+}
+
+func searchMovies(ctx context.Context, q *db.Queries, arg db.SearchMoviesParams, prefix bool) ([]db.SearchMoviesRow, error) {
+	if !prefix {
+		return q.SearchMovies(ctx, arg)
+	}
+	rows, err := q.SearchMoviesByPrefix(ctx, db.SearchMoviesByPrefixParams(arg))
+	movies := make([]db.SearchMoviesRow, len(rows))
+	for i, r := range rows {
+		movies[i] = db.SearchMoviesRow(r)
+	}
+	return movies, err
+}
+
+func searchPersons(ctx context.Context, q *db.Queries, arg db.SearchPersonsParams, prefix bool) ([]db.SearchPersonsRow, error) {
+	if !prefix {
+		return q.SearchPersons(ctx, arg)
+	}
+	rows, err := q.SearchPersonsByPrefix(ctx, db.SearchPersonsByPrefixParams(arg))
+	persons := make([]db.SearchPersonsRow, len(rows))
+	for i, r := range rows {
+		persons[i] = db.SearchPersonsRow(r)
+	}
+	return persons, err
+}
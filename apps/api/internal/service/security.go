@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+)
+
+// recordSecurityEvent appends to a user's security log. It is best effort like
+// recordActivity: the protective action has already been taken.
+func recordSecurityEvent(ctx context.Context, q *db.Queries, userID int32, typ, sessionID string, client dto.ClientInfo, metadata map[string]any) {
+	var raw []byte
+	if metadata != nil {
+		raw, _ = json.Marshal(metadata)
+	}
+
+	if err := q.CreateSecurityEvent(ctx, db.CreateSecurityEventParams{
+		UserID:    userID,
+		Type:      typ,
+		SessionID: optText(sessionID),
+		IpAddress: optText(client.IPAddress),
+		UserAgent: optText(client.UserAgent),
+		Metadata:  raw,
+	}); err != nil {
+		log.Printf("record %s security event for user %d: %v", typ, userID, err)
+	}
+}
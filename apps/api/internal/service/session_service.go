@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/mapper"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionUnknown  = errors.New("current session is unknown, sign in again to manage sessions")
+)
+
+// SessionService lists and revokes the devices a user is signed in on.
+// Revoking a session deletes its refresh token, and SessionValidator then
+// refuses the access tokens already issued for it.
+type SessionService struct {
+	queries *db.Queries
+}
+
+func NewSessionService(q *db.Queries) *SessionService {
+	return &SessionService{queries: q}
+}
+
+// List returns the user's unexpired sessions, flagging currentID
+func (s *SessionService) List(ctx context.Context, userID int32, currentID string) ([]dto.SessionResponse, error) {
+	sessions, err := s.queries.ListUserSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ToSessionResponses(sessions, currentID), nil
+}
+
+func (s *SessionService) Revoke(ctx context.Context, userID int32, id string) error {
+	n, err := s.queries.DeleteUserSession(ctx, db.DeleteUserSessionParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeOthers signs the user out everywhere except currentID
+func (s *SessionService) RevokeOthers(ctx context.Context, userID int32, currentID string) (int64, error) {
+	if currentID == "" {
+		return 0, ErrSessionUnknown
+	}
+	return s.queries.DeleteOtherUserSessions(ctx, db.DeleteOtherUserSessionsParams{UserID: userID, KeepID: currentID})
+}
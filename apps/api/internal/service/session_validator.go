@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// Channel the session revocation triggers publish on
+	revocationChannel = "session_revocations"
+
+	// How long a cached answer is trusted without hearing from the listener.
+	// Notifications make revocation immediate; the TTL only bounds how stale
+	// an instance can get while its listener is reconnecting.
+	sessionCacheTTL = 30 * time.Second
+
+	// Sessions cached at most; past this expired entries are swept, and if
+	// that frees nothing the cache starts over
+	sessionCacheSize = 100_000
+)
+
+// sessionRevocation is the NOTIFY payload written by notify_session_revocation().
+// Without a session id every session of the user is affected.
+type sessionRevocation struct {
+	UserID    int32  `json:"user_id"`
+	SessionID string `json:"session_id"`
+}
+
+type sessionEntry struct {
+	userID    int32
+	active    bool
+	checkedAt time.Time
+}
+
+// SessionValidator decides whether an access token's session still grants
+// access, so logging out, revoking a session or suspending a user takes effect
+// before the token expires. Answers are cached in memory, including negative
+// ones, and dropped as soon as Postgres announces a session was deleted or a
+// user's status changed.
+type SessionValidator struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+
+	mu      sync.RWMutex
+	entries map[string]sessionEntry
+	// Bumped by every invalidation, so a lookup that raced with one does not
+	// cache what it read before the change
+	generation uint64
+	closed     bool
+	cancel     context.CancelFunc
+}
+
+func NewSessionValidator(pool *pgxpool.Pool, q *db.Queries) *SessionValidator {
+	return &SessionValidator{
+		pool:    pool,
+		queries: q,
+		entries: make(map[string]sessionEntry),
+	}
+}
+
+// Start runs the LISTEN loop in the background until Close
+func (v *SessionValidator) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	v.mu.Lock()
+	v.cancel = cancel
+	v.mu.Unlock()
+
+	go v.run(ctx)
+}
+
+func (v *SessionValidator) Close() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.closed {
+		return
+	}
+	v.closed = true
+	if v.cancel != nil {
+		v.cancel()
+	}
+}
+
+// SessionActive reports whether sessionID exists, has not expired, belongs to
+// userID and that user is neither suspended nor banned
+func (v *SessionValidator) SessionActive(ctx context.Context, userID int32, sessionID string) (bool, error) {
+	now := time.Now()
+
+	v.mu.RLock()
+	e, ok := v.entries[sessionID]
+	generation := v.generation
+	v.mu.RUnlock()
+	if ok && now.Sub(e.checkedAt) < sessionCacheTTL {
+		return e.active && e.userID == userID, nil
+	}
+
+	e = sessionEntry{checkedAt: now}
+	row, err := v.queries.GetActiveSession(ctx, sessionID)
+	switch {
+	case err == nil:
+		e.userID = row.UserID
+		e.active = row.Status != db.UserStatusSUSPENDED && row.Status != db.UserStatusBANNED
+	case !errors.Is(err, pgx.ErrNoRows):
+		return false, err
+	}
+
+	v.store(sessionID, e, generation)
+	return e.active && e.userID == userID, nil
+}
+
+func (v *SessionValidator) store(sessionID string, e sessionEntry, generation uint64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.generation != generation {
+		return
+	}
+	if len(v.entries) >= sessionCacheSize {
+		for id, old := range v.entries {
+			if e.checkedAt.Sub(old.checkedAt) >= sessionCacheTTL {
+				delete(v.entries, id)
+			}
+		}
+		if len(v.entries) >= sessionCacheSize {
+			clear(v.entries)
+		}
+	}
+	v.entries[sessionID] = e
+}
+
+// invalidate drops what is cached about a session, or about every session of
+// the user when no session id is given. Revocations are rare next to lookups,
+// so a user-wide one simply scans the cache.
+func (v *SessionValidator) invalidate(r sessionRevocation) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.generation++
+	if r.SessionID != "" {
+		delete(v.entries, r.SessionID)
+		return
+	}
+	for id, e := range v.entries {
+		if e.userID == r.UserID {
+			delete(v.entries, id)
+		}
+	}
+}
+
+// reset forgets everything; revocations may have been missed while the
+// listener was disconnected
+func (v *SessionValidator) reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.generation++
+	clear(v.entries)
+}
+
+func (v *SessionValidator) run(ctx context.Context) {
+	for {
+		err := v.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("session revocation listener: %v, retrying in %s", err, listenRetryDelay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+// listen holds a dedicated connection taken out of the pool, since a LISTENing
+// connection must not be handed to other queries
+func (v *SessionValidator) listen(ctx context.Context) error {
+	conn, err := v.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	pgConn := conn.Hijack()
+	defer pgConn.Close(context.Background())
+
+	if _, err := pgConn.Exec(ctx, "LISTEN "+revocationChannel); err != nil {
+		return err
+	}
+	v.reset()
+
+	for {
+		n, err := pgConn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var r sessionRevocation
+		if err := json.Unmarshal([]byte(n.Payload), &r); err != nil {
+			log.Printf("session revocation listener: bad payload %q: %v", n.Payload, err)
+			v.reset()
+			continue
+		}
+		v.invalidate(r)
+	}
+}
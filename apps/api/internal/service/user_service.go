@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/mapper"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrProfilePrivate   = errors.New("this profile is private")
+	ErrWatchlistPrivate = errors.New("this watchlist is private")
+)
+
+type UserService struct {
+	queries *db.Queries
+}
+
+func NewUserService(q *db.Queries) *UserService {
+	return &UserService{queries: q}
+}
+
+// GetPublicProfile returns the public view of a user; viewerID is 0 for anonymous callers.
+// A private profile still resolves, but without its stats.
+func (s *UserService) GetPublicProfile(ctx context.Context, username string, viewerID int32) (*dto.PublicProfileResponse, error) {
+	user, err := getUserByUsername(ctx, s.queries, username)
+	if err != nil {
+		return nil, err
+	}
+
+	var isFollowing bool
+	if viewerID != 0 && viewerID != user.ID {
+		isFollowing, err = s.queries.IsFollowing(ctx, db.IsFollowingParams{FollowerID: viewerID, FollowingID: user.ID})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp := mapper.ToPublicProfileResponse(user, isFollowing)
+	if !canSeeProfile(user, viewerID) {
+		return &resp, nil
+	}
+
+	stats, err := s.queries.GetUserStats(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	histogram, err := s.queries.GetUserRatingHistogram(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	profileStats := mapper.ToProfileStats(stats, histogram, canSeeWatchlist(user, viewerID))
+	resp.Stats = &profileStats
+	return &resp, nil
+}
+
+// UpdateMe edits the caller's own profile and privacy settings
+func (s *UserService) UpdateMe(ctx context.Context, userID int32, req dto.UpdateMeRequest) (*dto.UserResponse, error) {
+	params := db.UpdateUserProfileParams{ID: userID}
+	if req.DisplayName != nil {
+		params.DisplayName = pgtype.Text{String: strings.TrimSpace(*req.DisplayName), Valid: true}
+	}
+	if req.Bio != nil {
+		params.Bio = pgtype.Text{String: strings.TrimSpace(*req.Bio), Valid: true}
+	}
+	if req.AvatarURL != nil {
+		params.AvatarUrl = pgtype.Text{String: strings.TrimSpace(*req.AvatarURL), Valid: true}
+	}
+	if req.ProfilePrivate != nil {
+		params.ProfilePrivate = pgtype.Bool{Bool: *req.ProfilePrivate, Valid: true}
+	}
+	if req.WatchlistPrivate != nil {
+		params.WatchlistPrivate = pgtype.Bool{Bool: *req.WatchlistPrivate, Valid: true}
+	}
+
+	user, err := s.queries.UpdateUserProfile(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	resp := mapper.ToUserResponse(user)
+	return &resp, nil
+}
+
+func canSeeProfile(user db.User, viewerID int32) bool {
+	return !user.ProfilePrivate || user.ID == viewerID
+}
+
+func canSeeWatchlist(user db.User, viewerID int32) bool {
+	return canSeeProfile(user, viewerID) && (!user.WatchlistPrivate || user.ID == viewerID)
+}
+
+// getVisibleUser resolves a username for listing that user's activity,
+// refusing private profiles to anyone but their owner
+func getVisibleUser(ctx context.Context, q *db.Queries, username string, viewerID int32) (db.User, error) {
+	user, err := getUserByUsername(ctx, q, username)
+	if err != nil {
+		return db.User{}, err
+	}
+	if !canSeeProfile(user, viewerID) {
+		return db.User{}, ErrProfilePrivate
+	}
+	return user, nil
+}
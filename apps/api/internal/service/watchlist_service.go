@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/MassoudJavadi/filmophilia/api/internal/dto"
+	"github.com/MassoudJavadi/filmophilia/api/internal/mapper"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// watchlistRankStep is the gap between neighbouring ranks after an append or a
+// rebalance. rank_position is a REAL, so with a few hundred items there is room
+// for roughly a dozen successive moves into the same gap before the midpoint
+// collides with a neighbour and the list has to be renumbered.
+const watchlistRankStep float32 = 1024
+
+var (
+	ErrWatchlistItemNotFound = errors.New("watchlist item not found")
+	ErrAlreadyInWatchlist    = errors.New("movie is already in your watchlist")
+	ErrInvalidMove           = errors.New("invalid position: neighbours must be other items of your watchlist, in order")
+)
+
+type WatchlistService struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewWatchlistService(pool *pgxpool.Pool, q *db.Queries) *WatchlistService {
+	return &WatchlistService{pool: pool, queries: q}
+}
+
+func (s *WatchlistService) List(ctx context.Context, userID int32, q dto.WatchlistQuery) (dto.PaginatedResponse[dto.WatchlistItemResponse], error) {
+	var watched pgtype.Bool
+	if q.Watched != nil {
+		watched = pgtype.Bool{Bool: *q.Watched, Valid: true}
+	}
+
+	total, err := s.queries.CountWatchlist(ctx, db.CountWatchlistParams{UserID: userID, Watched: watched})
+	if err != nil {
+		return dto.PaginatedResponse[dto.WatchlistItemResponse]{}, err
+	}
+
+	rows, err := s.queries.ListWatchlist(ctx, db.ListWatchlistParams{
+		UserID:  userID,
+		Watched: watched,
+		Limit:   q.Limit(),
+		Offset:  q.Offset(),
+	})
+	if err != nil {
+		return dto.PaginatedResponse[dto.WatchlistItemResponse]{}, err
+	}
+
+	return dto.NewPaginatedResponse(mapper.ToWatchlistItemResponses(rows), q.PaginationQuery, total), nil
+}
+
+// ListByUser shows another user's watchlist unless their profile or watchlist is private
+func (s *WatchlistService) ListByUser(ctx context.Context, username string, q dto.WatchlistQuery, viewerID int32) (dto.PaginatedResponse[dto.WatchlistItemResponse], error) {
+	user, err := getVisibleUser(ctx, s.queries, username, viewerID)
+	if err != nil {
+		return dto.PaginatedResponse[dto.WatchlistItemResponse]{}, err
+	}
+	if !canSeeWatchlist(user, viewerID) {
+		return dto.PaginatedResponse[dto.WatchlistItemResponse]{}, ErrWatchlistPrivate
+	}
+	return s.List(ctx, user.ID, q)
+}
+
+// Add appends a movie to the bottom of the user's watchlist
+func (s *WatchlistService) Add(ctx context.Context, userID int32, slug string, req dto.AddToWatchlistRequest) (*dto.WatchlistItemResponse, error) {
+	movie, err := getMovieBySlug(ctx, s.queries, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.queries.WithTx(tx)
+
+	if err := qtx.LockUserWatchlist(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	item, err := qtx.AddToWatchlist(ctx, db.AddToWatchlistParams{
+		UserID:  userID,
+		MovieID: movie.ID,
+		Notes:   optText(strings.TrimSpace(req.Notes)),
+		Step:    watchlistRankStep,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "watchlists_user_id_movie_id_key") {
+			return nil, ErrAlreadyInWatchlist
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	recordActivity(ctx, s.queries, userID, dto.ActivityWatchlisted, db.EntityTypeMOVIE, movie.ID, nil)
+
+	return s.get(ctx, userID, item.ID)
+}
+
+func (s *WatchlistService) UpdateNotes(ctx context.Context, userID, id int32, req dto.UpdateWatchlistItemRequest) (*dto.WatchlistItemResponse, error) {
+	n, err := s.queries.UpdateWatchlistNotes(ctx, db.UpdateWatchlistNotesParams{
+		Notes:  optText(strings.TrimSpace(*req.Notes)),
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrWatchlistItemNotFound
+	}
+	return s.get(ctx, userID, id)
+}
+
+func (s *WatchlistService) Remove(ctx context.Context, userID, id int32) error {
+	n, err := s.queries.DeleteWatchlistItem(ctx, db.DeleteWatchlistItemParams{ID: id, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrWatchlistItemNotFound
+	}
+	return nil
+}
+
+// Move gives an item a rank between its new neighbours, touching only that one
+// row. When float precision between the neighbours is exhausted the user's list
+// is renumbered once and the rank is computed again.
+func (s *WatchlistService) Move(ctx context.Context, userID, id int32, req dto.MoveWatchlistItemRequest) (*dto.WatchlistItemResponse, error) {
+	if req.AfterID == nil && req.BeforeID == nil {
+		return nil, ErrInvalidMove
+	}
+	if (req.AfterID != nil && *req.AfterID == id) || (req.BeforeID != nil && *req.BeforeID == id) {
+		return nil, ErrInvalidMove
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.queries.WithTx(tx)
+
+	if err := qtx.LockUserWatchlist(ctx, userID); err != nil {
+		return nil, err
+	}
+	if _, err := getWatchlistItem(ctx, qtx, userID, id); err != nil {
+		return nil, err
+	}
+
+	rank, ok, err := s.rankForMove(ctx, qtx, userID, id, req)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err := qtx.RebalanceWatchlist(ctx, db.RebalanceWatchlistParams{Step: watchlistRankStep, UserID: userID}); err != nil {
+			return nil, err
+		}
+		rank, ok, err = s.rankForMove(ctx, qtx, userID, id, req)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrInvalidMove
+		}
+	}
+
+	if err := qtx.SetWatchlistRank(ctx, db.SetWatchlistRankParams{
+		ID:           id,
+		RankPosition: pgtype.Float4{Float32: rank, Valid: true},
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return s.get(ctx, userID, id)
+}
+
+// SetWatched marks or unmarks an item as watched. Marking keeps the item in the
+// list and prompts for a rating when the user has not rated the movie yet.
+func (s *WatchlistService) SetWatched(ctx context.Context, userID, id int32, watched bool) (*dto.WatchedResponse, error) {
+	n, err := s.queries.SetWatchlistWatched(ctx, db.SetWatchlistWatchedParams{Watched: watched, ID: id, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrWatchlistItemNotFound
+	}
+
+	item, err := s.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.WatchedResponse{Item: *item}
+	if !watched {
+		return resp, nil
+	}
+
+	rating, err := s.queries.GetUserRating(ctx, db.GetUserRatingParams{UserID: userID, MovieID: item.Movie.ID})
+	switch {
+	case err == nil:
+		resp.Score = &rating.Score
+	case errors.Is(err, pgx.ErrNoRows):
+		resp.RatePrompt = true
+	default:
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *WatchlistService) get(ctx context.Context, userID, id int32) (*dto.WatchlistItemResponse, error) {
+	row, err := s.queries.GetWatchlistItemView(ctx, db.GetWatchlistItemViewParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrWatchlistItemNotFound
+		}
+		return nil, err
+	}
+	resp := mapper.ToWatchlistItemResponse(row)
+	return &resp, nil
+}
+
+// rankForMove resolves the requested neighbours, filling in the missing one
+// with the item actually adjacent to the given one, and returns a rank between
+// them. ok is false when the list needs rebalancing first.
+func (s *WatchlistService) rankForMove(ctx context.Context, q *db.Queries, userID, id int32, req dto.MoveWatchlistItemRequest) (float32, bool, error) {
+	var above, below *db.Watchlist
+
+	if req.AfterID != nil {
+		w, err := getWatchlistItem(ctx, q, userID, *req.AfterID)
+		if err != nil {
+			return 0, false, err
+		}
+		above = &w
+	}
+	if req.BeforeID != nil {
+		w, err := getWatchlistItem(ctx, q, userID, *req.BeforeID)
+		if err != nil {
+			return 0, false, err
+		}
+		below = &w
+	}
+
+	if above != nil && below == nil && above.RankPosition.Valid {
+		next, err := q.GetNextWatchlistItem(ctx, db.GetNextWatchlistItemParams{UserID: userID, ExcludeID: id, RankPosition: above.RankPosition})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, err
+		}
+		if err == nil {
+			below = &next
+		}
+	}
+	if below != nil && above == nil && below.RankPosition.Valid {
+		prev, err := q.GetPrevWatchlistItem(ctx, db.GetPrevWatchlistItemParams{UserID: userID, ExcludeID: id, RankPosition: below.RankPosition})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, err
+		}
+		if err == nil {
+			above = &prev
+		}
+	}
+
+	rank, ok := rankBetween(above, below)
+	return rank, ok, nil
+}
+
+// rankBetween picks a rank strictly between two neighbours, a nil neighbour
+// being the open end of the list. It reports false when the neighbours have no
+// rank yet or float32 can no longer represent a value between them.
+func rankBetween(above, below *db.Watchlist) (float32, bool) {
+	if (above != nil && !above.RankPosition.Valid) || (below != nil && !below.RankPosition.Valid) {
+		return 0, false
+	}
+
+	switch {
+	case above == nil && below == nil:
+		return watchlistRankStep, true
+	case above == nil:
+		hi := below.RankPosition.Float32
+		rank := hi - watchlistRankStep
+		return rank, rank < hi
+	case below == nil:
+		lo := above.RankPosition.Float32
+		rank := lo + watchlistRankStep
+		return rank, rank > lo
+	}
+
+	lo, hi := above.RankPosition.Float32, below.RankPosition.Float32
+	rank := lo + (hi-lo)/2
+	return rank, lo < rank && rank < hi
+}
+
+func getWatchlistItem(ctx context.Context, q *db.Queries, userID, id int32) (db.Watchlist, error) {
+	item, err := q.GetWatchlistItem(ctx, db.GetWatchlistItemParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Watchlist{}, ErrWatchlistItemNotFound
+		}
+		return db.Watchlist{}, err
+	}
+	return item, nil
+}
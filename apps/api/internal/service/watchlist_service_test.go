@@ -0,0 +1,68 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MassoudJavadi/filmophilia/api/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func ranked(rank float32) *db.Watchlist {
+	return &db.Watchlist{RankPosition: pgtype.Float4{Float32: rank, Valid: true}}
+}
+
+func TestRankBetween(t *testing.T) {
+	unranked := &db.Watchlist{}
+
+	tests := []struct {
+		name         string
+		above, below *db.Watchlist
+		want         float32
+		wantOK       bool
+	}{
+		{"empty list", nil, nil, watchlistRankStep, true},
+		{"before the first item", nil, ranked(1024), 0, true},
+		{"after the last item", ranked(2048), nil, 3072, true},
+		{"midpoint", ranked(1024), ranked(2048), 1536, true},
+		{"negative ranks", ranked(-3072), ranked(-1024), -2048, true},
+		{"fractional midpoint", ranked(1), ranked(2), 1.5, true},
+		{"adjacent floats", ranked(1), ranked(math.Nextafter32(1, 2)), 0, false},
+		{"equal neighbours", ranked(5), ranked(5), 0, false},
+		{"step lost at the top of float32", ranked(math.MaxFloat32), nil, 0, false},
+		{"step lost at the bottom of float32", nil, ranked(-math.MaxFloat32), 0, false},
+		{"unranked above", unranked, ranked(1024), 0, false},
+		{"unranked below", ranked(1024), unranked, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := rankBetween(tt.above, tt.below)
+			if ok != tt.wantOK {
+				t.Fatalf("rankBetween ok = %v, want %v (rank %v)", ok, tt.wantOK, got)
+			}
+			if ok && got != tt.want {
+				t.Errorf("rankBetween = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Repeatedly inserting at the same spot halves the gap each time; the
+// neighbours must stay strictly ordered until rankBetween gives up
+func TestRankBetweenExhaustion(t *testing.T) {
+	above, below := ranked(0), ranked(watchlistRankStep)
+	for i := 0; ; i++ {
+		rank, ok := rankBetween(above, below)
+		if !ok {
+			if i < 20 {
+				t.Fatalf("gave up after %d inserts, want at least 20", i)
+			}
+			return
+		}
+		if rank <= above.RankPosition.Float32 || rank >= below.RankPosition.Float32 {
+			t.Fatalf("insert %d: rank %v not between %v and %v", i, rank, above.RankPosition.Float32, below.RankPosition.Float32)
+		}
+		below = ranked(rank)
+	}
+}
@@ -0,0 +1,21 @@
+-- name: GetAccountByProvider :one
+SELECT * FROM accounts
+WHERE provider = $1 AND provider_account_id = $2;
+
+-- name: ListUserAccounts :many
+SELECT * FROM accounts
+WHERE user_id = $1
+ORDER BY provider;
+
+-- name: CountUserAccounts :one
+SELECT COUNT(*) FROM accounts WHERE user_id = $1;
+
+-- name: CreateAccount :one
+-- Provider access and refresh tokens are not kept: the API only needs the
+-- identity, and stored tokens would be a liability
+INSERT INTO accounts (user_id, provider, provider_account_id)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: DeleteUserAccount :execrows
+DELETE FROM accounts WHERE user_id = $1 AND provider = $2;
@@ -0,0 +1,26 @@
+-- name: CreateActivity :exec
+INSERT INTO activities (user_id, action, entity_type, entity_id, metadata)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListFeedActivities :many
+-- Keyset page of activities by users the viewer follows, newest first, joined
+-- with the actor and the movie, review or user each activity is about. Entity
+-- columns are NULL when the entity has since been deleted. Private profiles
+-- keep their activity out of everyone's feed.
+SELECT a.id, a.user_id, a.action, a.entity_type, a.entity_id, a.metadata, a.created_at,
+       u.username, u.display_name, u.avatar_url,
+       m.id AS movie_id, m.title AS movie_title, m.slug AS movie_slug,
+       m.poster_url AS movie_poster_url, m.release_date AS movie_release_date,
+       r.id AS review_id, r.title AS review_title,
+       tu.username AS target_username, tu.display_name AS target_display_name, tu.avatar_url AS target_avatar_url
+FROM activities a
+JOIN users u ON u.id = a.user_id
+LEFT JOIN reviews r ON a.entity_type = 'REVIEW' AND r.id = a.entity_id
+LEFT JOIN movies m ON m.id = CASE a.entity_type WHEN 'MOVIE' THEN a.entity_id WHEN 'REVIEW' THEN r.movie_id END
+LEFT JOIN users tu ON a.entity_type = 'USER' AND tu.id = a.entity_id
+WHERE a.user_id IN (SELECT f.following_id FROM follows f WHERE f.follower_id = sqlc.arg(viewer_id))
+  AND NOT u.profile_private
+  AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+       OR (a.created_at, a.id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::int))
+ORDER BY a.created_at DESC, a.id DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,55 @@
+-- name: CreateComment :one
+INSERT INTO comments (user_id, movie_id, parent_id, content)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetCommentByID :one
+SELECT * FROM comments WHERE id = $1;
+
+-- name: UpdateCommentContent :one
+UPDATE comments SET content = $2
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SoftDeleteComment :exec
+-- Keep the row so replies stay attached; readers render it as "[deleted]"
+UPDATE comments SET deleted_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: ListTopLevelComments :many
+-- Keyset page of thread roots, newest first; a NULL cursor starts from the top
+SELECT id, created_at FROM comments
+WHERE movie_id = sqlc.arg(movie_id)
+  AND parent_id IS NULL
+  AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL
+       OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::int))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListCommentTree :many
+-- Walks down from the given roots, stopping at max_depth (roots are depth 0)
+WITH RECURSIVE tree AS (
+    SELECT c.*, 0::int AS depth
+    FROM comments c
+    WHERE c.id = ANY(sqlc.arg(root_ids)::int[])
+    UNION ALL
+    SELECT c.*, t.depth + 1
+    FROM comments c
+    JOIN tree t ON c.parent_id = t.id
+    WHERE t.depth < sqlc.arg(max_depth)::int
+)
+SELECT t.id, t.user_id, t.movie_id, t.parent_id, t.content, t.like_count, t.deleted_at, t.created_at, t.updated_at,
+       t.depth::int AS depth,
+       (SELECT COUNT(*) FROM comments r WHERE r.parent_id = t.id) AS reply_count,
+       u.username, u.display_name, u.avatar_url
+FROM tree t
+JOIN users u ON u.id = t.user_id
+ORDER BY t.depth, t.created_at, t.id;
+
+-- name: PurgeDeletedComments :execrows
+-- Hard-deletes comments soft-deleted before the cutoff once nothing replies to
+-- them any more; a deleted parent with replies is kept so the thread holds
+-- together, and goes on a later run after its last reply has been purged
+DELETE FROM comments c
+WHERE c.deleted_at < sqlc.arg(before)
+  AND NOT EXISTS (SELECT 1 FROM comments r WHERE r.parent_id = c.id);
@@ -0,0 +1,53 @@
+-- name: CreateFollow :execrows
+-- Idempotent; zero rows affected means the follow already existed
+INSERT INTO follows (follower_id, following_id)
+VALUES ($1, $2)
+ON CONFLICT (follower_id, following_id) DO NOTHING;
+
+-- name: DeleteFollow :execrows
+DELETE FROM follows WHERE follower_id = $1 AND following_id = $2;
+
+-- name: IsFollowing :one
+SELECT EXISTS (
+    SELECT 1 FROM follows WHERE follower_id = $1 AND following_id = $2
+);
+
+-- name: CountFollowers :one
+SELECT COUNT(*) FROM follows WHERE following_id = $1;
+
+-- name: CountFollowing :one
+SELECT COUNT(*) FROM follows WHERE follower_id = $1;
+
+-- name: ListFollowers :many
+-- Users following $1, most recent first
+SELECT u.id, u.username, u.display_name, u.avatar_url, f.created_at AS followed_at
+FROM follows f
+JOIN users u ON u.id = f.follower_id
+WHERE f.following_id = $1
+ORDER BY f.created_at DESC, f.id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: ListFollowing :many
+-- Users $1 follows, most recent first
+SELECT u.id, u.username, u.display_name, u.avatar_url, f.created_at AS followed_at
+FROM follows f
+JOIN users u ON u.id = f.following_id
+WHERE f.follower_id = $1
+ORDER BY f.created_at DESC, f.id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: ListMutualFollows :many
+-- Users that $1 follows and who follow $1 back
+SELECT u.id, u.username, u.display_name, u.avatar_url, f.created_at AS followed_at
+FROM follows f
+JOIN follows back ON back.follower_id = f.following_id AND back.following_id = f.follower_id
+JOIN users u ON u.id = f.following_id
+WHERE f.follower_id = $1
+ORDER BY u.username
+LIMIT $2 OFFSET $3;
+
+-- name: CountMutualFollows :one
+SELECT COUNT(*)
+FROM follows f
+JOIN follows back ON back.follower_id = f.following_id AND back.following_id = f.follower_id
+WHERE f.follower_id = $1;
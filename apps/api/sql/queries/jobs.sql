@@ -0,0 +1,14 @@
+-- name: TryJobLock :one
+-- Transaction-scoped, so the lock is released with the job's transaction and
+-- a replica that dies mid-job cannot hold it
+SELECT pg_try_advisory_xact_lock(hashtext('jobs'), hashtext(sqlc.arg(job)::text));
+
+-- name: ClaimJobRun :execrows
+-- Records a run of the job unless another replica ran it within the interval;
+-- no row is affected then. Runs in the job's transaction, so a failed run is
+-- not recorded.
+INSERT INTO job_runs (name, last_run_at)
+VALUES (sqlc.arg(job)::text, NOW())
+ON CONFLICT (name) DO UPDATE
+SET last_run_at = NOW()
+WHERE job_runs.last_run_at <= NOW() - make_interval(secs => sqlc.arg(interval_seconds)::int);
@@ -21,6 +21,61 @@ INSERT INTO movies (
 ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 RETURNING id;
 
+-- name: GetMovieByID :one
+-- Fetch a single movie by id, e.g. to read back trigger-maintained stats
+SELECT * FROM movies WHERE id = $1;
+
+-- name: GetMovieBySlug :one
+-- Fetch a single movie by its public slug
+SELECT * FROM movies WHERE slug = $1;
+
+-- name: ListMovies :many
+-- Paginated catalog listing; a NULL filter is ignored
+SELECT m.* FROM movies m
+WHERE (sqlc.narg('genre')::text IS NULL OR EXISTS (
+        SELECT 1 FROM movie_genres mg
+        JOIN genres g ON g.id = mg.genre_id
+        WHERE mg.movie_id = m.id AND g.slug = sqlc.narg('genre')::text
+    ))
+  AND (sqlc.narg('year_from')::int IS NULL OR m.release_date >= make_date(sqlc.narg('year_from')::int, 1, 1))
+  AND (sqlc.narg('year_to')::int IS NULL OR m.release_date < make_date(sqlc.narg('year_to')::int + 1, 1, 1))
+  AND (sqlc.narg('runtime_min')::int IS NULL OR m.runtime >= sqlc.narg('runtime_min')::int)
+  AND (sqlc.narg('runtime_max')::int IS NULL OR m.runtime <= sqlc.narg('runtime_max')::int)
+  AND (sqlc.narg('language')::text IS NULL OR lower(m.original_language) = lower(sqlc.narg('language')::text))
+  AND (sqlc.narg('country')::text IS NULL OR lower(m.country) = lower(sqlc.narg('country')::text))
+ORDER BY m.release_date DESC NULLS LAST, m.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountMovies :one
+-- Total number of movies matching the same filters as ListMovies
+SELECT COUNT(*) FROM movies m
+WHERE (sqlc.narg('genre')::text IS NULL OR EXISTS (
+        SELECT 1 FROM movie_genres mg
+        JOIN genres g ON g.id = mg.genre_id
+        WHERE mg.movie_id = m.id AND g.slug = sqlc.narg('genre')::text
+    ))
+  AND (sqlc.narg('year_from')::int IS NULL OR m.release_date >= make_date(sqlc.narg('year_from')::int, 1, 1))
+  AND (sqlc.narg('year_to')::int IS NULL OR m.release_date < make_date(sqlc.narg('year_to')::int + 1, 1, 1))
+  AND (sqlc.narg('runtime_min')::int IS NULL OR m.runtime >= sqlc.narg('runtime_min')::int)
+  AND (sqlc.narg('runtime_max')::int IS NULL OR m.runtime <= sqlc.narg('runtime_max')::int)
+  AND (sqlc.narg('language')::text IS NULL OR lower(m.original_language) = lower(sqlc.narg('language')::text))
+  AND (sqlc.narg('country')::text IS NULL OR lower(m.country) = lower(sqlc.narg('country')::text));
+
+-- ============================================================
+-- GENRES QUERIES
+-- ============================================================
+
+-- name: ListGenres :many
+-- All genres, alphabetically, for building catalog filters
+SELECT * FROM genres ORDER BY name;
+
+-- name: ListGenresByMovie :many
+-- Genres attached to a single movie
+SELECT g.* FROM genres g
+JOIN movie_genres mg ON mg.genre_id = g.id
+WHERE mg.movie_id = $1
+ORDER BY g.name;
+
 -- ============================================================
 -- CREDITS QUERIES
 -- ============================================================
@@ -28,4 +83,13 @@ RETURNING id;
 -- name: CreateCredit :exec
 -- Create a link between a movie and a person (cast/crew)
 INSERT INTO credits (movie_id, person_id, department, role, character)
-VALUES ($1, $2, $3, $4, $5);
\ No newline at end of file
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListMovieCredits :many
+-- Credits of one department for a movie, in billing order
+SELECT p.id AS person_id, p.name, p.slug, p.photo_url, c.role, c.character, c."order"
+FROM credits c
+JOIN persons p ON p.id = c.person_id
+WHERE c.movie_id = $1 AND c.department = $2
+ORDER BY c."order" NULLS LAST, c.id
+LIMIT $3;
@@ -0,0 +1,49 @@
+-- name: CreateNotification :one
+INSERT INTO notifications (user_id, type, title, content, metadata)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: CreateFollowerNotifications :execrows
+-- Fans one notification out to every follower of a user in a single statement
+INSERT INTO notifications (user_id, type, title, content, metadata)
+SELECT f.follower_id, sqlc.arg(type)::notification_type, sqlc.arg(title)::varchar, sqlc.narg(content)::text, sqlc.arg(metadata)::jsonb
+FROM follows f
+WHERE f.following_id = sqlc.arg(following_id);
+
+-- name: HasNotification :one
+-- Whether the user already has a notification of this type whose metadata contains the given keys
+SELECT EXISTS (
+    SELECT 1 FROM notifications
+    WHERE user_id = sqlc.arg(user_id) AND type = sqlc.arg(type) AND metadata @> sqlc.arg(metadata)::jsonb
+);
+
+-- name: ListNotifications :many
+-- Unread first, then newest first; served by notifications_user_unread_idx
+SELECT * FROM notifications
+WHERE user_id = sqlc.arg(user_id)
+  AND (NOT sqlc.arg(unread_only)::boolean OR is_read = FALSE)
+ORDER BY is_read, created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountNotifications :one
+SELECT COUNT(*) FROM notifications
+WHERE user_id = sqlc.arg(user_id)
+  AND (NOT sqlc.arg(unread_only)::boolean OR is_read = FALSE);
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND is_read = FALSE;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications SET is_read = TRUE WHERE id = $1 AND user_id = $2;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications SET is_read = TRUE WHERE user_id = $1 AND is_read = FALSE;
+
+-- name: DeleteNotification :execrows
+DELETE FROM notifications WHERE id = $1 AND user_id = $2;
+
+-- name: GetNotification :one
+SELECT * FROM notifications WHERE id = $1;
+
+-- name: DeleteOldReadNotifications :execrows
+DELETE FROM notifications WHERE is_read = TRUE AND created_at < sqlc.arg(before);
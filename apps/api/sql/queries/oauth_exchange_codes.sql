@@ -0,0 +1,12 @@
+-- name: CreateOAuthExchangeCode :exec
+INSERT INTO oauth_exchange_codes (code_hash, user_id, return_to, expires_at)
+VALUES ($1, $2, $3, $4);
+
+-- name: ConsumeOAuthExchangeCode :one
+-- Redeems an unexpired code; a second redemption finds no row
+DELETE FROM oauth_exchange_codes
+WHERE code_hash = $1 AND expires_at > NOW()
+RETURNING user_id, return_to;
+
+-- name: DeleteExpiredOAuthExchangeCodes :execrows
+DELETE FROM oauth_exchange_codes WHERE expires_at < NOW();
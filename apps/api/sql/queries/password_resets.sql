@@ -0,0 +1,23 @@
+-- name: CreatePasswordResetToken :exec
+INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+VALUES ($1, $2, $3);
+
+-- name: HasRecentPasswordResetToken :one
+-- Whether a reset link went out to the user within the cooldown
+SELECT EXISTS (
+    SELECT 1 FROM password_reset_tokens
+    WHERE user_id = sqlc.arg(user_id)
+      AND created_at > NOW() - make_interval(secs => sqlc.arg(cooldown_seconds)::int)
+);
+
+-- name: ConsumePasswordResetToken :one
+-- Redeems an unused, unexpired token; a second redemption finds no row
+UPDATE password_reset_tokens SET used_at = NOW()
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING user_id;
+
+-- name: DeleteUserPasswordResetTokens :exec
+DELETE FROM password_reset_tokens WHERE user_id = $1;
+
+-- name: DeleteExpiredPasswordResetTokens :execrows
+DELETE FROM password_reset_tokens WHERE expires_at < NOW();
@@ -0,0 +1,28 @@
+-- name: UpsertRating :one
+INSERT INTO ratings (user_id, movie_id, score)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, movie_id) DO UPDATE SET score = EXCLUDED.score
+RETURNING *;
+
+-- name: GetUserRating :one
+SELECT * FROM ratings WHERE user_id = $1 AND movie_id = $2;
+
+-- name: DeleteRating :execrows
+DELETE FROM ratings WHERE user_id = $1 AND movie_id = $2;
+
+-- name: ListUserRatings :many
+-- A user's ratings joined with the rated movie; sort is one of recent, score_desc, score_asc, title
+SELECT r.score, r.created_at, r.updated_at,
+       m.id AS movie_id, m.title, m.slug, m.poster_url, m.release_date
+FROM ratings r
+JOIN movies m ON m.id = r.movie_id
+WHERE r.user_id = sqlc.arg(user_id)
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'score_desc' THEN r.score END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'score_asc' THEN r.score END ASC,
+    CASE WHEN sqlc.arg(sort)::text = 'title' THEN m.title END ASC,
+    r.updated_at DESC, r.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountUserRatings :one
+SELECT COUNT(*) FROM ratings WHERE user_id = $1;
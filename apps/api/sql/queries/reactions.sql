@@ -0,0 +1,42 @@
+-- name: UpsertReviewReaction :one
+-- Set or change the caller's reaction; the partial unique index makes this one row per user per review
+INSERT INTO reactions (user_id, review_id, type)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, review_id) WHERE review_id IS NOT NULL
+DO UPDATE SET type = EXCLUDED.type
+RETURNING *;
+
+-- name: UpsertCommentReaction :one
+INSERT INTO reactions (user_id, comment_id, type)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, comment_id) WHERE comment_id IS NOT NULL
+DO UPDATE SET type = EXCLUDED.type
+RETURNING *;
+
+-- name: DeleteReviewReaction :execrows
+DELETE FROM reactions WHERE user_id = $1 AND review_id = $2;
+
+-- name: DeleteCommentReaction :execrows
+DELETE FROM reactions WHERE user_id = $1 AND comment_id = $2;
+
+-- name: CountReviewReactions :many
+-- Per-type breakdown for a batch of reviews
+SELECT review_id, type, COUNT(*) AS count
+FROM reactions
+WHERE review_id = ANY(sqlc.arg(review_ids)::int[])
+GROUP BY review_id, type;
+
+-- name: CountCommentReactions :many
+-- Per-type breakdown for a batch of comments
+SELECT comment_id, type, COUNT(*) AS count
+FROM reactions
+WHERE comment_id = ANY(sqlc.arg(comment_ids)::int[])
+GROUP BY comment_id, type;
+
+-- name: ListUserReviewReactions :many
+SELECT review_id, type FROM reactions
+WHERE user_id = sqlc.arg(user_id) AND review_id = ANY(sqlc.arg(review_ids)::int[]);
+
+-- name: ListUserCommentReactions :many
+SELECT comment_id, type FROM reactions
+WHERE user_id = sqlc.arg(user_id) AND comment_id = ANY(sqlc.arg(comment_ids)::int[]);
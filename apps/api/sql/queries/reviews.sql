@@ -0,0 +1,66 @@
+-- name: CreateReview :one
+INSERT INTO reviews (user_id, movie_id, title, content)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetReviewByID :one
+SELECT * FROM reviews WHERE id = $1;
+
+-- name: UpdateReview :one
+-- Partial update; a NULL argument keeps the current value
+UPDATE reviews
+SET title = COALESCE(sqlc.narg(title), title),
+    content = COALESCE(sqlc.narg(content), content)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteReview :exec
+DELETE FROM reviews WHERE id = $1;
+
+-- name: GetReviewView :one
+-- A review with its author, the author's own rating of the movie and a movie reference
+SELECT rv.id, rv.user_id, rv.movie_id, rv.title, rv.content, rv.like_count, rv.created_at, rv.updated_at,
+       u.username, u.display_name AS author_display_name, u.avatar_url AS author_avatar_url,
+       r.score AS author_rating,
+       m.title AS movie_title, m.slug AS movie_slug, m.poster_url AS movie_poster_url
+FROM reviews rv
+JOIN users u ON u.id = rv.user_id
+JOIN movies m ON m.id = rv.movie_id
+LEFT JOIN ratings r ON r.user_id = rv.user_id AND r.movie_id = rv.movie_id
+WHERE rv.id = $1;
+
+-- name: ListMovieReviews :many
+-- Same shape as GetReviewView; sort is newest or most_liked (reviews_like_count_idx)
+SELECT rv.id, rv.user_id, rv.movie_id, rv.title, rv.content, rv.like_count, rv.created_at, rv.updated_at,
+       u.username, u.display_name AS author_display_name, u.avatar_url AS author_avatar_url,
+       r.score AS author_rating,
+       m.title AS movie_title, m.slug AS movie_slug, m.poster_url AS movie_poster_url
+FROM reviews rv
+JOIN users u ON u.id = rv.user_id
+JOIN movies m ON m.id = rv.movie_id
+LEFT JOIN ratings r ON r.user_id = rv.user_id AND r.movie_id = rv.movie_id
+WHERE rv.movie_id = sqlc.arg(movie_id)
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'most_liked' THEN rv.like_count END DESC NULLS LAST,
+    rv.created_at DESC, rv.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountMovieReviews :one
+SELECT COUNT(*) FROM reviews WHERE movie_id = $1;
+
+-- name: ListUserReviews :many
+-- Same shape as GetReviewView, newest first
+SELECT rv.id, rv.user_id, rv.movie_id, rv.title, rv.content, rv.like_count, rv.created_at, rv.updated_at,
+       u.username, u.display_name AS author_display_name, u.avatar_url AS author_avatar_url,
+       r.score AS author_rating,
+       m.title AS movie_title, m.slug AS movie_slug, m.poster_url AS movie_poster_url
+FROM reviews rv
+JOIN users u ON u.id = rv.user_id
+JOIN movies m ON m.id = rv.movie_id
+LEFT JOIN ratings r ON r.user_id = rv.user_id AND r.movie_id = rv.movie_id
+WHERE rv.user_id = $1
+ORDER BY rv.created_at DESC, rv.id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountUserReviews :one
+SELECT COUNT(*) FROM reviews WHERE user_id = $1;
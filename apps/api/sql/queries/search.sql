@@ -0,0 +1,56 @@
+-- name: SetSimilarityThreshold :exec
+-- Scope the pg_trgm % operator threshold to the current transaction
+SELECT set_config('pg_trgm.similarity_threshold', sqlc.arg(threshold)::text, true);
+
+-- name: SearchMovies :many
+-- Fuzzy title match served by movies_title_trgm_idx
+SELECT id, title, slug, poster_url, release_date,
+       similarity(title, sqlc.arg(query)::text)::real AS score
+FROM movies
+WHERE title % sqlc.arg(query)::text
+ORDER BY score DESC, user_rating_count DESC NULLS LAST, id
+LIMIT sqlc.arg('limit');
+
+-- name: SearchPersons :many
+-- Fuzzy name match served by persons_name_trgm_idx
+SELECT id, name, slug, photo_url,
+       similarity(name, sqlc.arg(query)::text)::real AS score
+FROM persons
+WHERE name % sqlc.arg(query)::text
+ORDER BY score DESC, id
+LIMIT sqlc.arg('limit');
+
+-- name: CountSearchResults :one
+-- Per-type match counts for search facets
+SELECT
+    (SELECT COUNT(*) FROM movies WHERE title % sqlc.arg(query)::text) AS movie_count,
+    (SELECT COUNT(*) FROM persons WHERE name % sqlc.arg(query)::text) AS person_count;
+
+-- name: SetWordSimilarityThreshold :exec
+-- Scope the pg_trgm <% operator threshold to the current transaction
+SELECT set_config('pg_trgm.word_similarity_threshold', sqlc.arg(threshold)::text, true);
+
+-- name: SearchMoviesByPrefix :many
+-- Type-ahead title match: scores the query against the best matching run of
+-- words in the title, so "Godf" finds "The Godfather". Served by movies_title_trgm_idx
+SELECT id, title, slug, poster_url, release_date,
+       word_similarity(sqlc.arg(query)::text, title)::real AS score
+FROM movies
+WHERE sqlc.arg(query)::text <% title
+ORDER BY score DESC, user_rating_count DESC NULLS LAST, id
+LIMIT sqlc.arg('limit');
+
+-- name: SearchPersonsByPrefix :many
+-- Type-ahead name match served by persons_name_trgm_idx
+SELECT id, name, slug, photo_url,
+       word_similarity(sqlc.arg(query)::text, name)::real AS score
+FROM persons
+WHERE sqlc.arg(query)::text <% name
+ORDER BY score DESC, id
+LIMIT sqlc.arg('limit');
+
+-- name: CountSearchResultsByPrefix :one
+-- Per-type match counts for type-ahead search facets
+SELECT
+    (SELECT COUNT(*) FROM movies WHERE sqlc.arg(query)::text <% title) AS movie_count,
+    (SELECT COUNT(*) FROM persons WHERE sqlc.arg(query)::text <% name) AS person_count;
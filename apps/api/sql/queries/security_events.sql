@@ -0,0 +1,12 @@
+-- name: CreateSecurityEvent :exec
+INSERT INTO security_events (user_id, type, session_id, ip_address, user_agent, metadata)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: ListUserSecurityEvents :many
+SELECT * FROM security_events
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountUserSecurityEvents :one
+SELECT COUNT(*) FROM security_events WHERE user_id = $1;
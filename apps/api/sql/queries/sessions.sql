@@ -7,6 +7,7 @@ RETURNING *;
 SELECT * FROM sessions WHERE id = $1;
 
 -- name: GetSessionByRefreshToken :one
+-- refresh_token holds the SHA-256 hex of the token, never the token itself
 SELECT * FROM sessions WHERE refresh_token = $1 LIMIT 1;
 
 -- name: UpdateSession :exec
@@ -20,10 +21,54 @@ DELETE FROM sessions WHERE id = $1;
 -- name: DeleteUserSessions :exec
 DELETE FROM sessions WHERE user_id = $1;
 
--- name: DeleteExpiredSessions :exec
+-- name: DeleteExpiredSessions :execrows
 DELETE FROM sessions WHERE expires_at < NOW();
 
 
 -- name: DeleteSessionByRefreshToken :exec
 DELETE FROM sessions
-WHERE refresh_token = $1;
\ No newline at end of file
+WHERE refresh_token = $1;
+-- name: RotateSession :execrows
+-- Swaps the refresh token of a session in place; zero rows means the old
+-- token was already rotated away by a concurrent refresh
+UPDATE sessions
+SET refresh_token = sqlc.arg(new_refresh_token),
+    expires_at = sqlc.arg(expires_at),
+    user_agent = sqlc.arg(user_agent),
+    ip_address = sqlc.arg(ip_address),
+    last_used_at = NOW()
+WHERE id = sqlc.arg(id) AND refresh_token = sqlc.arg(old_refresh_token);
+
+-- name: ListUserSessions :many
+SELECT * FROM sessions
+WHERE user_id = $1 AND expires_at > NOW()
+ORDER BY last_used_at DESC, created_at DESC;
+
+-- name: DeleteUserSession :execrows
+DELETE FROM sessions WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteOtherUserSessions :execrows
+DELETE FROM sessions WHERE user_id = sqlc.arg(user_id) AND id <> sqlc.arg(keep_id);
+
+-- name: CreateRotatedRefreshToken :exec
+INSERT INTO rotated_refresh_tokens (token_hash, session_id)
+VALUES ($1, $2);
+
+-- name: GetRotatedRefreshToken :one
+-- Finds the family a rotated-out token belonged to
+SELECT r.session_id, r.rotated_at, s.user_id
+FROM rotated_refresh_tokens r
+JOIN sessions s ON s.id = r.session_id
+WHERE r.token_hash = $1;
+
+-- name: DeleteOldRotatedRefreshTokens :execrows
+-- Rotated tokens this old had expired anyway, so their reuse proves nothing
+DELETE FROM rotated_refresh_tokens WHERE rotated_at < sqlc.arg(before);
+
+-- name: GetActiveSession :one
+-- The owner of a session that still grants access, checked on every
+-- authenticated request (through a cache)
+SELECT s.user_id, u.status
+FROM sessions s
+JOIN users u ON u.id = s.user_id
+WHERE s.id = $1 AND s.expires_at > NOW();
@@ -14,3 +14,86 @@ SELECT * FROM users WHERE username = $1;
 
 -- name: UpdateUserStatus :exec
 UPDATE users SET status = $2 WHERE id = $1;
+
+-- name: UpdateUserProfile :one
+-- Omitted (NULL) fields are kept; an empty string clears a text field
+UPDATE users SET
+    display_name = CASE WHEN sqlc.narg(display_name)::text IS NULL THEN display_name
+                        ELSE NULLIF(sqlc.narg(display_name)::text, '') END,
+    bio = CASE WHEN sqlc.narg(bio)::text IS NULL THEN bio
+               ELSE NULLIF(sqlc.narg(bio)::text, '') END,
+    avatar_url = CASE WHEN sqlc.narg(avatar_url)::text IS NULL THEN avatar_url
+                      ELSE NULLIF(sqlc.narg(avatar_url)::text, '') END,
+    profile_private = COALESCE(sqlc.narg(profile_private)::boolean, profile_private),
+    watchlist_private = COALESCE(sqlc.narg(watchlist_private)::boolean, watchlist_private)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetUserStats :one
+SELECT
+    (SELECT COUNT(*) FROM ratings r WHERE r.user_id = sqlc.arg(user_id)) AS rating_count,
+    (SELECT COUNT(*) FROM reviews rv WHERE rv.user_id = sqlc.arg(user_id)) AS review_count,
+    (SELECT COUNT(*) FROM follows f WHERE f.following_id = sqlc.arg(user_id)) AS follower_count,
+    (SELECT COUNT(*) FROM follows f WHERE f.follower_id = sqlc.arg(user_id)) AS following_count,
+    (SELECT COUNT(*) FROM watchlists w WHERE w.user_id = sqlc.arg(user_id)) AS watchlist_count;
+
+-- name: GetUserRatingHistogram :many
+SELECT score, COUNT(*) AS count
+FROM ratings
+WHERE user_id = $1
+GROUP BY score
+ORDER BY score;
+
+-- name: SetStatusChangeContext :exec
+-- Hands reason and acting admin to log_user_status_change() for the current transaction
+SELECT set_config('filmophilia.status_reason', sqlc.arg(reason)::text, true),
+       set_config('filmophilia.status_changed_by', sqlc.arg(changed_by)::int::text, true);
+
+-- name: ListUserStatusLogs :many
+-- Status history of a user, newest first, with the acting admin's username
+SELECT l.id, l.old_status, l.new_status, l.reason, l.changed_by, l.created_at,
+       a.username AS changed_by_username
+FROM user_status_logs l
+LEFT JOIN users a ON a.id = l.changed_by
+WHERE l.user_id = sqlc.arg(user_id)
+ORDER BY l.created_at DESC, l.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountUserStatusLogs :one
+SELECT COUNT(*) FROM user_status_logs WHERE user_id = $1;
+
+-- name: SetStatusChangeReason :exec
+-- Like SetStatusChangeContext for changes the user made themselves
+SELECT set_config('filmophilia.status_reason', sqlc.arg(reason)::text, true);
+
+-- name: ClaimVerificationSend :execrows
+-- Stamps a verification send unless the user is verified or one went out
+-- within the cooldown; zero rows means the send must be skipped
+UPDATE users SET verification_sent_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND is_verified = FALSE
+  AND (verification_sent_at IS NULL
+       OR verification_sent_at <= NOW() - make_interval(secs => sqlc.arg(cooldown_seconds)::int));
+
+-- name: VerifyUserEmail :one
+-- Only succeeds while the email is still the one the token was issued for
+UPDATE users SET
+    is_verified = TRUE,
+    status = CASE WHEN status = 'PENDING' THEN 'ACTIVE'::user_status ELSE status END
+WHERE id = sqlc.arg(id) AND email = sqlc.arg(email)
+RETURNING *;
+
+-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = $2 WHERE id = $1;
+
+-- name: CreateOAuthUser :one
+-- The provider has verified the email, so the account starts out active
+INSERT INTO users (email, username, display_name, avatar_url, status, is_verified)
+VALUES ($1, $2, $3, $4, 'ACTIVE', TRUE)
+RETURNING *;
+
+-- name: UsernameExists :one
+SELECT EXISTS (SELECT 1 FROM users WHERE username = $1);
+
+-- name: SetUserAvatarIfEmpty :exec
+UPDATE users SET avatar_url = $2 WHERE id = $1 AND avatar_url IS NULL;
@@ -0,0 +1,81 @@
+-- name: LockUserWatchlist :exec
+-- Serializes rank changes of one user's watchlist for the rest of the transaction
+SELECT pg_advisory_xact_lock(hashtext('watchlists'), sqlc.arg(user_id)::int);
+
+-- name: AddToWatchlist :one
+-- New items go to the bottom of the list, one step below the current last rank
+INSERT INTO watchlists (user_id, movie_id, notes, rank_position)
+VALUES (
+    sqlc.arg(user_id),
+    sqlc.arg(movie_id),
+    sqlc.narg(notes),
+    COALESCE((SELECT MAX(w.rank_position) FROM watchlists w WHERE w.user_id = sqlc.arg(user_id)), 0) + sqlc.arg(step)::real
+)
+RETURNING *;
+
+-- name: GetWatchlistItem :one
+SELECT * FROM watchlists WHERE id = $1 AND user_id = $2;
+
+-- name: GetNextWatchlistItem :one
+-- The item directly below the given rank, ignoring the item being moved
+SELECT * FROM watchlists
+WHERE user_id = sqlc.arg(user_id) AND id <> sqlc.arg(exclude_id) AND rank_position > sqlc.arg(rank_position)
+ORDER BY rank_position, id
+LIMIT 1;
+
+-- name: GetPrevWatchlistItem :one
+-- The item directly above the given rank, ignoring the item being moved
+SELECT * FROM watchlists
+WHERE user_id = sqlc.arg(user_id) AND id <> sqlc.arg(exclude_id) AND rank_position < sqlc.arg(rank_position)
+ORDER BY rank_position DESC, id DESC
+LIMIT 1;
+
+-- name: GetWatchlistItemView :one
+SELECT w.id, w.notes, w.watched_at, w.created_at,
+       m.id AS movie_id, m.title, m.slug, m.poster_url, m.release_date
+FROM watchlists w
+JOIN movies m ON m.id = w.movie_id
+WHERE w.id = $1 AND w.user_id = $2;
+
+-- name: ListWatchlist :many
+-- A user's watchlist in rank order, optionally only watched or unwatched items
+SELECT w.id, w.notes, w.watched_at, w.created_at,
+       m.id AS movie_id, m.title, m.slug, m.poster_url, m.release_date
+FROM watchlists w
+JOIN movies m ON m.id = w.movie_id
+WHERE w.user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(watched)::boolean IS NULL OR (w.watched_at IS NOT NULL) = sqlc.narg(watched)::boolean)
+ORDER BY w.rank_position NULLS LAST, w.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountWatchlist :one
+SELECT COUNT(*) FROM watchlists
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(watched)::boolean IS NULL OR (watched_at IS NOT NULL) = sqlc.narg(watched)::boolean);
+
+-- name: UpdateWatchlistNotes :execrows
+UPDATE watchlists SET notes = sqlc.narg(notes)
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id);
+
+-- name: SetWatchlistRank :exec
+UPDATE watchlists SET rank_position = $2 WHERE id = $1;
+
+-- name: RebalanceWatchlist :exec
+-- Renumbers a user's whole list with evenly spaced ranks, keeping the current order
+UPDATE watchlists w
+SET rank_position = o.rn * sqlc.arg(step)::real
+FROM (
+    SELECT id, ROW_NUMBER() OVER (ORDER BY rank_position NULLS LAST, id) AS rn
+    FROM watchlists
+    WHERE user_id = sqlc.arg(user_id)
+) o
+WHERE w.id = o.id;
+
+-- name: SetWatchlistWatched :execrows
+-- Keeps the original timestamp when an item is marked watched twice
+UPDATE watchlists
+SET watched_at = CASE WHEN sqlc.arg(watched)::boolean THEN COALESCE(watched_at, NOW()) END
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id);
+
+-- name: DeleteWatchlistItem :execrows
+DELETE FROM watchlists WHERE id = $1 AND user_id = $2;
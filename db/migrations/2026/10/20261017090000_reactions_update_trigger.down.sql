@@ -0,0 +1,33 @@
+DROP TRIGGER IF EXISTS reactions_like_count_update ON reactions;
+
+-- Restore the INSERT/DELETE-only version from the initial schema
+CREATE OR REPLACE FUNCTION update_like_counts()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        IF OLD.review_id IS NOT NULL THEN
+            UPDATE reviews SET like_count = (
+                SELECT COUNT(*) FROM reactions WHERE review_id = OLD.review_id
+            ) WHERE id = OLD.review_id;
+        END IF;
+        IF OLD.comment_id IS NOT NULL THEN
+            UPDATE comments SET like_count = (
+                SELECT COUNT(*) FROM reactions WHERE comment_id = OLD.comment_id
+            ) WHERE id = OLD.comment_id;
+        END IF;
+        RETURN OLD;
+    ELSE
+        IF NEW.review_id IS NOT NULL THEN
+            UPDATE reviews SET like_count = (
+                SELECT COUNT(*) FROM reactions WHERE review_id = NEW.review_id
+            ) WHERE id = NEW.review_id;
+        END IF;
+        IF NEW.comment_id IS NOT NULL THEN
+            UPDATE comments SET like_count = (
+                SELECT COUNT(*) FROM reactions WHERE comment_id = NEW.comment_id
+            ) WHERE id = NEW.comment_id;
+        END IF;
+        RETURN NEW;
+    END IF;
+END;
+$$ LANGUAGE plpgsql;
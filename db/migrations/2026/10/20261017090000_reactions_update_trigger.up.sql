@@ -0,0 +1,39 @@
+-- Recount like_count on UPDATE as well, so changing a reaction in place
+-- (including moving it to another target) keeps both targets in sync.
+CREATE OR REPLACE FUNCTION update_like_counts()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('DELETE', 'UPDATE') THEN
+        IF OLD.review_id IS NOT NULL THEN
+            UPDATE reviews SET like_count = (
+                SELECT COUNT(*) FROM reactions WHERE review_id = OLD.review_id
+            ) WHERE id = OLD.review_id;
+        END IF;
+        IF OLD.comment_id IS NOT NULL THEN
+            UPDATE comments SET like_count = (
+                SELECT COUNT(*) FROM reactions WHERE comment_id = OLD.comment_id
+            ) WHERE id = OLD.comment_id;
+        END IF;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        IF NEW.review_id IS NOT NULL THEN
+            UPDATE reviews SET like_count = (
+                SELECT COUNT(*) FROM reactions WHERE review_id = NEW.review_id
+            ) WHERE id = NEW.review_id;
+        END IF;
+        IF NEW.comment_id IS NOT NULL THEN
+            UPDATE comments SET like_count = (
+                SELECT COUNT(*) FROM reactions WHERE comment_id = NEW.comment_id
+            ) WHERE id = NEW.comment_id;
+        END IF;
+        RETURN NEW;
+    END IF;
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reactions_like_count_update
+    AFTER UPDATE ON reactions
+    FOR EACH ROW EXECUTE FUNCTION update_like_counts();
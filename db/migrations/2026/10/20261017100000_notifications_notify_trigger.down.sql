@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS notifications_notify ON notifications;
+DROP FUNCTION IF EXISTS notify_notification_change();
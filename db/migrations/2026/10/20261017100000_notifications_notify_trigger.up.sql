@@ -0,0 +1,28 @@
+-- Announce notification changes on the "notifications" channel so every API
+-- instance can push them to the SSE streams it holds. Payloads only carry ids
+-- to stay far below the 8000 byte NOTIFY limit. Read/delete payloads have no
+-- notification id, so Postgres folds a "mark all read" into one message.
+CREATE OR REPLACE FUNCTION notify_notification_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        PERFORM pg_notify('notifications', json_build_object(
+            'user_id', NEW.user_id,
+            'notification_id', NEW.id
+        )::text);
+        RETURN NEW;
+    END IF;
+
+    IF TG_OP = 'UPDATE' THEN
+        PERFORM pg_notify('notifications', json_build_object('user_id', NEW.user_id)::text);
+        RETURN NEW;
+    END IF;
+
+    PERFORM pg_notify('notifications', json_build_object('user_id', OLD.user_id)::text);
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notifications_notify
+    AFTER INSERT OR UPDATE OF is_read OR DELETE ON notifications
+    FOR EACH ROW EXECUTE FUNCTION notify_notification_change();
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS watchlist_private,
+    DROP COLUMN IF EXISTS profile_private;
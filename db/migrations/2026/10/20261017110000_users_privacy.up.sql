@@ -0,0 +1,5 @@
+-- A private profile hides its stats, lists and feed activity from everyone but
+-- the owner; a private watchlist hides only the watchlist.
+ALTER TABLE users
+    ADD COLUMN profile_private   BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN watchlist_private BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,19 @@
+-- Record who changed a user's status and why. The application passes both as
+-- transaction-local settings right before UpdateUserStatus; a change made
+-- without them (e.g. from psql) is still logged, with NULL reason and actor.
+CREATE OR REPLACE FUNCTION log_user_status_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.status IS DISTINCT FROM NEW.status THEN
+        INSERT INTO user_status_logs (user_id, old_status, new_status, reason, changed_by)
+        VALUES (
+            NEW.id,
+            OLD.status,
+            NEW.status,
+            NULLIF(current_setting('filmophilia.status_reason', true), ''),
+            NULLIF(current_setting('filmophilia.status_changed_by', true), '')::int
+        );
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
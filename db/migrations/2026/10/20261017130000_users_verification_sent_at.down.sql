@@ -0,0 +1,3 @@
+-- The verification backfill is kept: there is no telling which accounts it touched
+ALTER TABLE users
+    DROP COLUMN IF EXISTS verification_sent_at;
@@ -0,0 +1,12 @@
+-- When the last verification email went out, so resends can be rate limited
+-- across API instances without extra state.
+ALTER TABLE users
+    ADD COLUMN verification_sent_at TIMESTAMPTZ;
+
+-- Nothing verified email before this migration, and sign-in now requires it.
+-- Accounts that already exist are trusted as they were, so nobody is locked
+-- out on deploy; only accounts created from here on go through verification.
+UPDATE users
+SET is_verified = TRUE,
+    status = CASE WHEN status = 'PENDING' THEN 'ACTIVE'::user_status ELSE status END
+WHERE is_verified = FALSE;
@@ -0,0 +1,14 @@
+-- Password reset tokens are stored as SHA-256 hashes so a database leak does
+-- not hand out working reset links. A token is single use: used_at is set
+-- when it is redeemed.
+CREATE TABLE password_reset_tokens (
+    id          SERIAL PRIMARY KEY,
+    user_id     INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash  VARCHAR(64) NOT NULL UNIQUE,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    used_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX password_reset_tokens_user_idx ON password_reset_tokens (user_id, created_at DESC);
+CREATE INDEX password_reset_tokens_expires_at_idx ON password_reset_tokens (expires_at);
@@ -0,0 +1,4 @@
+-- Refreshing now rotates the token inside the same session row, so a session
+-- keeps its id and sign-in time; last_used_at shows when it was last refreshed.
+ALTER TABLE sessions
+    ADD COLUMN last_used_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS security_events;
+DROP INDEX IF EXISTS sessions_refresh_token_idx;
+DROP TABLE IF EXISTS rotated_refresh_tokens;
+
+-- Hashed tokens cannot be turned back into plaintext; everyone signs in again
+DELETE FROM sessions;
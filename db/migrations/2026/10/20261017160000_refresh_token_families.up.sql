@@ -0,0 +1,30 @@
+-- A session is a refresh token family: refreshing rotates the token inside the
+-- session row. Tokens rotated out are remembered until the session ends, so
+-- presenting one again (a sign the token was stolen) revokes the whole family.
+CREATE TABLE rotated_refresh_tokens (
+    token_hash  VARCHAR(64) PRIMARY KEY,
+    session_id  VARCHAR(255) NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    rotated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX rotated_refresh_tokens_session_idx ON rotated_refresh_tokens (session_id);
+
+-- Refresh tokens are stored as SHA-256 hashes from now on
+UPDATE sessions
+SET refresh_token = encode(sha256(convert_to(refresh_token, 'UTF8')), 'hex')
+WHERE refresh_token IS NOT NULL;
+
+CREATE UNIQUE INDEX sessions_refresh_token_idx ON sessions (refresh_token);
+
+CREATE TABLE security_events (
+    id          SERIAL PRIMARY KEY,
+    user_id     INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type        VARCHAR(50) NOT NULL,
+    session_id  VARCHAR(255),
+    ip_address  VARCHAR(45),
+    user_agent  TEXT,
+    metadata    JSONB,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX security_events_user_idx ON security_events (user_id, created_at DESC);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS accounts_user_provider_key;
+
+UPDATE users SET password_hash = '' WHERE password_hash IS NULL;
+
+ALTER TABLE users
+    ALTER COLUMN password_hash SET NOT NULL;
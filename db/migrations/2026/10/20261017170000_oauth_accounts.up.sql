@@ -0,0 +1,8 @@
+-- Users who only sign in through a provider have no password instead of an
+-- empty one, and a user links at most one account per provider.
+ALTER TABLE users
+    ALTER COLUMN password_hash DROP NOT NULL;
+
+UPDATE users SET password_hash = NULL WHERE password_hash = '';
+
+CREATE UNIQUE INDEX accounts_user_provider_key ON accounts (user_id, provider);
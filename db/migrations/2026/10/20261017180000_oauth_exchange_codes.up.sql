@@ -0,0 +1,13 @@
+-- One-time codes that hand a finished OAuth sign-in over to the frontend.
+-- The browser is redirected to the frontend with the code, which the
+-- frontend trades for tokens within a minute. Only the SHA-256 hash is
+-- stored, and a code is deleted when it is redeemed.
+CREATE TABLE oauth_exchange_codes (
+    code_hash   VARCHAR(64) PRIMARY KEY,
+    user_id     INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    return_to   TEXT,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX oauth_exchange_codes_expires_at_idx ON oauth_exchange_codes (expires_at);
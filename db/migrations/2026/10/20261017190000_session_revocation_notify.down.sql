@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS users_notify_revocation ON users;
+DROP TRIGGER IF EXISTS sessions_notify_revocation ON sessions;
+DROP FUNCTION IF EXISTS notify_session_revocation();
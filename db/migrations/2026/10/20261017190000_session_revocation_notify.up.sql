@@ -0,0 +1,28 @@
+-- Announce revoked access on the "session_revocations" channel so every API
+-- instance drops what it has cached about the session or user at once.
+-- Deleting a session ends it (logout, revoke, ban, password change), and a
+-- status change can lock a user out even where no session was deleted.
+CREATE OR REPLACE FUNCTION notify_session_revocation()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'sessions' THEN
+        PERFORM pg_notify('session_revocations', json_build_object(
+            'user_id', OLD.user_id,
+            'session_id', OLD.id
+        )::text);
+        RETURN OLD;
+    END IF;
+
+    PERFORM pg_notify('session_revocations', json_build_object('user_id', NEW.id)::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER sessions_notify_revocation
+    AFTER DELETE ON sessions
+    FOR EACH ROW EXECUTE FUNCTION notify_session_revocation();
+
+CREATE TRIGGER users_notify_revocation
+    AFTER UPDATE OF status ON users
+    FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION notify_session_revocation();
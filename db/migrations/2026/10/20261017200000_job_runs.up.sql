@@ -0,0 +1,6 @@
+-- When each maintenance job last ran, so that across replicas a job runs once
+-- per interval rather than once per replica per interval
+CREATE TABLE job_runs (
+    name        TEXT PRIMARY KEY,
+    last_run_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,19 @@
+-- Restore the definition from the initial schema; the recount is kept
+CREATE OR REPLACE FUNCTION update_movie_rating_stats()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE movies SET
+            average_rating = COALESCE((SELECT AVG(score)::REAL FROM ratings WHERE movie_id = OLD.movie_id), 0),
+            rating_count = (SELECT COUNT(*) FROM ratings WHERE movie_id = OLD.movie_id)
+        WHERE id = OLD.movie_id;
+        RETURN OLD;
+    ELSE
+        UPDATE movies SET
+            average_rating = COALESCE((SELECT AVG(score)::REAL FROM ratings WHERE movie_id = NEW.movie_id), 0),
+            rating_count = (SELECT COUNT(*) FROM ratings WHERE movie_id = NEW.movie_id)
+        WHERE id = NEW.movie_id;
+        RETURN NEW;
+    END IF;
+END;
+$$ LANGUAGE plpgsql;
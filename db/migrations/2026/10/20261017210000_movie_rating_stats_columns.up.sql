@@ -0,0 +1,26 @@
+-- update_movie_rating_stats() still wrote average_rating and rating_count,
+-- which 20260207120000 renamed; plpgsql only resolves them when the trigger
+-- fires, so every write to ratings failed. Point it at the renamed columns.
+CREATE OR REPLACE FUNCTION update_movie_rating_stats()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE movies SET
+            user_avg_rating = COALESCE((SELECT AVG(score)::REAL FROM ratings WHERE movie_id = OLD.movie_id), 0),
+            user_rating_count = (SELECT COUNT(*) FROM ratings WHERE movie_id = OLD.movie_id)
+        WHERE id = OLD.movie_id;
+        RETURN OLD;
+    ELSE
+        UPDATE movies SET
+            user_avg_rating = COALESCE((SELECT AVG(score)::REAL FROM ratings WHERE movie_id = NEW.movie_id), 0),
+            user_rating_count = (SELECT COUNT(*) FROM ratings WHERE movie_id = NEW.movie_id)
+        WHERE id = NEW.movie_id;
+        RETURN NEW;
+    END IF;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Recount from the ratings table so every movie starts out consistent
+UPDATE movies SET
+    user_avg_rating = COALESCE((SELECT AVG(score)::REAL FROM ratings WHERE movie_id = movies.id), 0),
+    user_rating_count = (SELECT COUNT(*) FROM ratings WHERE movie_id = movies.id);